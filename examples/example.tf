@@ -5,8 +5,63 @@ provider "qpid" {
   model_version = "v7.1"
   skip_cert_verification = true
   certificate_file = "./qpid-cert.pem"
+  retry_max_attempts = 5
+  retry_base_backoff = "500ms"
+  retry_max_backoff = "10s"
+  request_timeout = "60s"
+  max_concurrent_requests = 4
+  requests_per_second = 20
+  headers = {
+    "X-Correlation-ID" = "terraform"
+  }
 }
 
+# Alternatively, authenticate against broker OAuth2 authentication provider
+# using access tokens obtained with client credentials grant
+#
+# provider "qpid" {
+#   endpoint = "https://127.0.0.1:8080"
+#   model_version = "v7.1"
+#   oauth2 {
+#     token_url = "https://auth.example.com/oauth2/token"
+#     client_id = "terraform"
+#     client_secret = "secret"
+#     scopes = ["qpid-management"]
+#   }
+# }
+
+# Manage BDB_HA replication group: fail over between the brokers and
+# apply changes of virtual host objects on the master
+#
+# provider "qpid" {
+#   endpoints = ["https://broker1:8080", "https://broker2:8080", "https://broker3:8080"]
+#   username = "guest"
+#   password = "guest"
+#   model_version = "v7.1"
+# }
+
+# Alternatively, log in with SCRAM SASL mechanism instead of sending
+# the password with every request using basic authentication
+#
+# provider "qpid" {
+#   endpoint = "http://127.0.0.1:8080"
+#   username = "guest"
+#   password = "guest"
+#   model_version = "v7.1"
+#   sasl_mechanism = "SCRAM-SHA-256"
+# }
+
+# Alternatively, authenticate against broker External authentication provider
+# using TLS client certificate
+#
+# provider "qpid" {
+#   endpoint = "https://127.0.0.1:8080"
+#   model_version = "v7.1"
+#   certificate_file = "./qpid-cert.pem"
+#   client_certificate_file = "./client-cert.pem"
+#   client_key_file = "./client-key.pem"
+# }
+
 
 # Create a virtual host node 'foo' with initial configuration
 resource "qpid_virtual_host_node" "foo" {
@@ -4,6 +4,7 @@ require (
 	github.com/hashicorp/terraform v0.12.19 // indirect
 	github.com/hashicorp/terraform-plugin-sdk v1.0.0
 	github.com/terraform-providers/terraform-provider-template v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20190820162420-60c769a6c586
 	google.golang.org/grpc v1.23.0
 )
 
@@ -0,0 +1,146 @@
+// Package api provides typed model of Qpid Broker-J configured objects and the client operating on them.
+// The client is built on top of any ConfiguredObjectClient, for example, qpid.Client of the provider.
+// The provider resources keep working with attribute maps derived from their schemas,
+// the typed client is intended for tooling built on top of the provider client.
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// QueryOptions are parameters of requests getting configured objects
+type QueryOptions struct {
+	// Actuals requests attribute values as set, without resolving context variables
+	Actuals bool
+	// Depth of children to include, zero leaves the broker default
+	Depth int
+	// Oversize is the length after which string values are truncated, zero leaves the broker default
+	Oversize int
+	// ExcludeInheritedContext excludes context variables inherited from the ancestors
+	ExcludeInheritedContext bool
+}
+
+// Values returns the options as request query parameters
+func (o *QueryOptions) Values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+
+	v.Set("actuals", strconv.FormatBool(o.Actuals))
+	if o.Depth > 0 {
+		v.Set("depth", strconv.Itoa(o.Depth))
+	}
+	if o.Oversize > 0 {
+		v.Set("oversize", strconv.Itoa(o.Oversize))
+	}
+	if o.ExcludeInheritedContext {
+		v.Set("excludeInheritedContext", "true")
+	}
+	return v
+}
+
+// ConfiguredObjectClient provides operations on broker configured objects of any category.
+// The objects are identified by category and path made of the names of ancestors and object itself,
+// for example, queue 'foo' on virtual host 'bar' of node 'baz' is identified by "queue" and []string{"baz", "bar", "foo"}.
+type ConfiguredObjectClient interface {
+	Create(category string, parents []string, attributes *map[string]interface{}) (*http.Response, error)
+	Get(category string, path []string, options *QueryOptions) (*map[string]interface{}, error)
+	Update(category string, path []string, attributes *map[string]interface{}) (*http.Response, error)
+	Delete(category string, path []string) (*http.Response, error)
+	List(category string, parents []string, options *QueryOptions) (*[]map[string]interface{}, error)
+}
+
+// Client operates on typed broker objects.
+// The objects are read with effective attribute values, thus, context variable references are resolved.
+type Client struct {
+	objects ConfiguredObjectClient
+}
+
+// NewClient constructs Client operating via given ConfiguredObjectClient
+func NewClient(objects ConfiguredObjectClient) *Client {
+	return &Client{objects: objects}
+}
+
+var effectiveValues = &QueryOptions{Actuals: false}
+
+func (c *Client) get(category string, path []string, object interface{}) error {
+	attributes, err := c.objects.Get(category, path, effectiveValues)
+	if err != nil {
+		return err
+	}
+	return Decode(attributes, object)
+}
+
+func (c *Client) list(category string, parents []string, objects interface{}) error {
+	attributes, err := c.objects.List(category, parents, effectiveValues)
+	if err != nil {
+		return err
+	}
+	return Decode(attributes, objects)
+}
+
+func (c *Client) create(category string, parents []string, object interface{}) error {
+	attributes, err := Encode(object)
+	if err != nil {
+		return err
+	}
+	return checkResponse(c.objects.Create(category, parents, attributes))
+}
+
+func (c *Client) update(category string, path []string, object interface{}) error {
+	attributes, err := Encode(object)
+	if err != nil {
+		return err
+	}
+	return checkResponse(c.objects.Update(category, path, attributes))
+}
+
+func (c *Client) delete(category string, path []string) error {
+	return checkResponse(c.objects.Delete(category, path))
+}
+
+func checkResponse(res *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return NewQpidAPIError(res)
+	}
+	return nil
+}
+
+// Decode converts attributes returned by ConfiguredObjectClient into given typed object or slice of objects
+func Decode(attributes interface{}, object interface{}) error {
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, object)
+	if err != nil {
+		return fmt.Errorf("unable to decode qpid object: %v", err)
+	}
+	return nil
+}
+
+// Encode converts typed object into attributes accepted by ConfiguredObjectClient, unset attributes are omitted
+func Encode(object interface{}) (*map[string]interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	attributes := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err = decoder.Decode(&attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &attributes, nil
+}
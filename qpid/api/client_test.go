@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeObjects is ConfiguredObjectClient keeping objects in memory by category and path
+type fakeObjects struct {
+	objects map[string]map[string]interface{}
+	options []*QueryOptions
+}
+
+func newFakeObjects() *fakeObjects {
+	return &fakeObjects{objects: map[string]map[string]interface{}{}}
+}
+
+func objectKey(category string, path []string) string {
+	return category + ":" + strings.Join(path, "/")
+}
+
+func fakeResponse(category string, path []string, status int, body string) *http.Response {
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(status)
+	_, _ = recorder.WriteString(body)
+	resp := recorder.Result()
+	resp.Request = httptest.NewRequest(http.MethodGet, "/api/latest/"+category+"/"+strings.Join(path, "/"), nil)
+	return resp
+}
+
+func (f *fakeObjects) Create(category string, parents []string, attributes *map[string]interface{}) (*http.Response, error) {
+	name, _ := (*attributes)["name"].(string)
+	path := append(append([]string{}, parents...), name)
+	if _, exists := f.objects[objectKey(category, path)]; exists {
+		return fakeResponse(category, parents, http.StatusConflict, `{"errorMessage": "Object with name '`+name+`' already exists"}`), nil
+	}
+	object := map[string]interface{}{"id": fmt.Sprintf("id-%d", len(f.objects)), "createdTime": 1572000000000}
+	for key, value := range *attributes {
+		object[key] = value
+	}
+	f.objects[objectKey(category, path)] = object
+	return fakeResponse(category, parents, http.StatusCreated, ""), nil
+}
+
+func (f *fakeObjects) Get(category string, path []string, options *QueryOptions) (*map[string]interface{}, error) {
+	f.options = append(f.options, options)
+	object, exists := f.objects[objectKey(category, path)]
+	if !exists {
+		return nil, NewQpidAPIError(fakeResponse(category, path, http.StatusNotFound, `{"errorMessage": "Not Found"}`))
+	}
+	return &object, nil
+}
+
+func (f *fakeObjects) Update(category string, path []string, attributes *map[string]interface{}) (*http.Response, error) {
+	object, exists := f.objects[objectKey(category, path)]
+	if !exists {
+		return fakeResponse(category, path, http.StatusNotFound, "Not Found"), nil
+	}
+	for key, value := range *attributes {
+		object[key] = value
+	}
+	return fakeResponse(category, path, http.StatusOK, ""), nil
+}
+
+func (f *fakeObjects) Delete(category string, path []string) (*http.Response, error) {
+	delete(f.objects, objectKey(category, path))
+	return fakeResponse(category, path, http.StatusOK, ""), nil
+}
+
+func (f *fakeObjects) List(category string, parents []string, options *QueryOptions) (*[]map[string]interface{}, error) {
+	f.options = append(f.options, options)
+	objects := []map[string]interface{}{}
+	prefix := objectKey(category, parents) + "/"
+	for key, object := range f.objects {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(strings.TrimPrefix(key, prefix), "/") {
+			objects = append(objects, object)
+		}
+	}
+	return &objects, nil
+}
+
+func TestEncode(t *testing.T) {
+	queue := &Queue{
+		ConfiguredObject:        ConfiguredObject{Name: String("foo"), Durable: Bool(false)},
+		AlternateBinding:        &AlternateBinding{Destination: String("bar")},
+		MaximumDeliveryAttempts: Int(0),
+		MaximumQueueDepthBytes:  Int64(1 << 40),
+	}
+	attributes, err := Encode(queue)
+	if err != nil {
+		t.Fatalf("unable to encode queue: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":                    "foo",
+		"durable":                 false,
+		"alternateBinding":        map[string]interface{}{"destination": "bar"},
+		"maximumDeliveryAttempts": json.Number("0"),
+		"maximumQueueDepthBytes":  json.Number("1099511627776"),
+	}
+	if !reflect.DeepEqual(*attributes, expected) {
+		t.Errorf("unexpected attributes %v, expected %v", *attributes, expected)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	attributes := &map[string]interface{}{
+		"id":                     "123",
+		"name":                   "foo",
+		"type":                   "standard",
+		"createdTime":            float64(1572000000000),
+		"maximumQueueDepthBytes": float64(-1),
+		"context":                map[string]interface{}{"x": "y"},
+		"unknownAttribute":       true,
+	}
+	queue := &Queue{}
+	err := Decode(attributes, queue)
+	if err != nil {
+		t.Fatalf("unable to decode queue: %v", err)
+	}
+	if queue.GetID() != "123" || queue.GetName() != "foo" || queue.GetType() != "standard" ||
+		*queue.CreatedTime != 1572000000000 || *queue.MaximumQueueDepthBytes != -1 || queue.Context["x"] != "y" {
+		t.Errorf("unexpected queue %+v", queue)
+	}
+	if queue.Durable != nil || queue.AlternateBinding != nil || queue.Description != nil {
+		t.Errorf("attributes not returned by the broker are set %+v", queue)
+	}
+
+	err = Decode(&map[string]interface{}{"maximumQueueDepthBytes": "unlimited"}, queue)
+	if err == nil || !strings.Contains(err.Error(), "unable to decode qpid object") {
+		t.Errorf("unexpected error decoding invalid attribute: %v", err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	objects := newFakeObjects()
+	client := NewClient(objects)
+
+	err := client.CreateQueue("node", "host", &Queue{ConfiguredObject: ConfiguredObject{Name: String("foo")}, MaximumDeliveryAttempts: Int(3)})
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+	err = client.CreateQueue("node", "host", &Queue{ConfiguredObject: ConfiguredObject{Name: String("foo")}})
+	if apiError, ok := err.(*QpidAPIError); !ok || apiError.StatusCode != http.StatusConflict || apiError.Message != "Object with name 'foo' already exists" {
+		t.Errorf("unexpected error creating existing queue: %v", err)
+	}
+
+	queue, err := client.GetQueue("node", "host", "foo")
+	if err != nil || queue.GetID() == "" || *queue.MaximumDeliveryAttempts != 3 || *queue.CreatedTime != 1572000000000 {
+		t.Fatalf("unexpected queue %+v: %v", queue, err)
+	}
+
+	err = client.UpdateQueue("node", "host", "foo", &Queue{MaximumDeliveryAttempts: Int(5)})
+	if err != nil {
+		t.Fatalf("unable to update queue: %v", err)
+	}
+	queues, err := client.ListQueues("node", "host")
+	if err != nil || len(queues) != 1 || queues[0].GetName() != "foo" || *queues[0].MaximumDeliveryAttempts != 5 {
+		t.Fatalf("unexpected queues %+v: %v", queues, err)
+	}
+	for _, options := range objects.options {
+		if options == nil || options.Actuals {
+			t.Errorf("objects are not read with effective values: %+v", options)
+		}
+	}
+
+	err = client.UpdateQueue("node", "host", "bar", &Queue{})
+	if apiError, ok := err.(*QpidAPIError); !ok || !IsNotFound(err) || apiError.Message != "Not Found" {
+		t.Errorf("unexpected error updating missing queue: %v", err)
+	}
+
+	err = client.DeleteQueue("node", "host", "foo")
+	if err != nil {
+		t.Fatalf("unable to delete queue: %v", err)
+	}
+	_, err = client.GetQueue("node", "host", "foo")
+	if !IsNotFound(err) {
+		t.Errorf("unexpected error getting deleted queue: %v", err)
+	}
+}
+
+func TestClientListBindings(t *testing.T) {
+	objects := newFakeObjects()
+	objects.objects[objectKey("exchange", []string{"node", "host", "foo"})] = map[string]interface{}{
+		"name": "foo",
+		"bindings": []interface{}{
+			map[string]interface{}{"bindingKey": "#", "destination": "bar", "arguments": map[string]interface{}{"x-filter-jms-selector": "a > 1", "x-priority": 5, "x-exclusive": true}},
+		},
+	}
+
+	bindings, err := NewClient(objects).ListBindings("node", "host", "foo")
+	if err != nil || len(bindings) != 1 {
+		t.Fatalf("unexpected bindings %+v: %v", bindings, err)
+	}
+	if StringValue(bindings[0].BindingKey) != "#" || StringValue(bindings[0].Destination) != "bar" || bindings[0].Arguments["x-filter-jms-selector"] != "a > 1" ||
+		bindings[0].Arguments["x-priority"] != float64(5) || bindings[0].Arguments["x-exclusive"] != true {
+		t.Errorf("unexpected binding %+v", bindings[0])
+	}
+
+	_, err = NewClient(objects).ListBindings("node", "host", "bar")
+	if !IsNotFound(err) {
+		t.Errorf("unexpected error listing bindings of missing exchange: %v", err)
+	}
+}
+
+func TestQpidAPIError(t *testing.T) {
+	responses := map[string]*http.Response{
+		"qpid API error on '/api/latest/queue/a/b': 404 Not Found: Not Found":                 fakeResponse("queue", []string{"a", "b"}, http.StatusNotFound, `{"errorMessage": "Not Found"}`),
+		"qpid API error on '/api/latest/queue/a/b': 422 Unprocessable Entity: invalid value":  fakeResponse("queue", []string{"a", "b"}, http.StatusUnprocessableEntity, "invalid value\n"),
+		"qpid API error on '/api/latest/queue/a/b': 500 Internal Server Error":                fakeResponse("queue", []string{"a", "b"}, http.StatusInternalServerError, ""),
+		"qpid API error on '/api/latest/queue/a/b': 400 Bad Request: unknown attribute 'foo'": fakeResponse("queue", []string{"a", "b"}, http.StatusBadRequest, `{"message": "unknown attribute 'foo'"}`),
+	}
+	for expected, resp := range responses {
+		err := NewQpidAPIError(resp)
+		if err.Error() != expected {
+			t.Errorf("unexpected error '%s', expected '%s'", err.Error(), expected)
+		}
+		if IsNotFound(err) != (err.StatusCode == http.StatusNotFound) {
+			t.Errorf("unexpected result of IsNotFound for %v", err)
+		}
+	}
+	if IsNotFound(fmt.Errorf("unable to read queue: %w", NewQpidAPIError(fakeResponse("queue", nil, http.StatusNotFound, "")))) != true {
+		t.Errorf("wrapped not found error is not recognized")
+	}
+}
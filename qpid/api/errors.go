@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound is matched by errors reporting that the requested broker object does not exist
+var ErrNotFound = errors.New("qpid object not found")
+
+// QpidAPIError represents an error response of the broker management API
+type QpidAPIError struct {
+	StatusCode int
+	Message    string
+	Path       string
+}
+
+func (e *QpidAPIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("qpid API error on '%s': %d %s", e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("qpid API error on '%s': %d %s: %s", e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is reports whether the error matches the target, ErrNotFound is matched by 404 responses
+func (e *QpidAPIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound returns true if the error reports that the requested broker object does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// NewQpidAPIError creates QpidAPIError from the error response, the broker error message is read from the body
+func NewQpidAPIError(res *http.Response) *QpidAPIError {
+	apiError := &QpidAPIError{StatusCode: res.StatusCode}
+	if res.Request != nil && res.Request.URL != nil {
+		apiError.Path = res.Request.URL.Path
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil || len(body) == 0 {
+		return apiError
+	}
+
+	var message map[string]interface{}
+	if json.Unmarshal(body, &message) == nil {
+		for _, key := range []string{"errorMessage", "message"} {
+			if m, ok := message[key]; ok && m != nil {
+				apiError.Message = fmt.Sprintf("%v", m)
+				return apiError
+			}
+		}
+	}
+
+	apiError.Message = strings.TrimSpace(string(body))
+	return apiError
+}
@@ -0,0 +1,294 @@
+package api
+
+// ConfiguredObject holds the attributes common to all broker objects.
+// Optional attributes are pointers, nil values are not sent to the broker.
+type ConfiguredObject struct {
+	ID              *string           `json:"id,omitempty"`
+	Name            *string           `json:"name,omitempty"`
+	Type            *string           `json:"type,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	Durable         *bool             `json:"durable,omitempty"`
+	Context         map[string]string `json:"context,omitempty"`
+	DesiredState    *string           `json:"desiredState,omitempty"`
+	State           *string           `json:"state,omitempty"`
+	CreatedBy       *string           `json:"createdBy,omitempty"`
+	CreatedTime     *int64            `json:"createdTime,omitempty"`
+	LastUpdatedBy   *string           `json:"lastUpdatedBy,omitempty"`
+	LastUpdatedTime *int64            `json:"lastUpdatedTime,omitempty"`
+}
+
+// GetID returns object id or empty string if id is not set
+func (o *ConfiguredObject) GetID() string {
+	return StringValue(o.ID)
+}
+
+// GetName returns object name or empty string if name is not set
+func (o *ConfiguredObject) GetName() string {
+	return StringValue(o.Name)
+}
+
+// GetType returns object type or empty string if type is not set
+func (o *ConfiguredObject) GetType() string {
+	return StringValue(o.Type)
+}
+
+// VirtualHostNode ...
+type VirtualHostNode struct {
+	ConfiguredObject
+	DefaultVirtualHostNode          *bool    `json:"defaultVirtualHostNode,omitempty"`
+	VirtualHostInitialConfiguration *string  `json:"virtualHostInitialConfiguration,omitempty"`
+	StorePath                       *string  `json:"storePath,omitempty"`
+	ConnectionURL                   *string  `json:"connectionUrl,omitempty"`
+	ConnectionPoolType              *string  `json:"connectionPoolType,omitempty"`
+	Username                        *string  `json:"username,omitempty"`
+	Password                        *string  `json:"password,omitempty"`
+	TableNamePrefix                 *string  `json:"tableNamePrefix,omitempty"`
+	GroupName                       *string  `json:"groupName,omitempty"`
+	Address                         *string  `json:"address,omitempty"`
+	HelperAddress                   *string  `json:"helperAddress,omitempty"`
+	HelperNodeName                  *string  `json:"helperNodeName,omitempty"`
+	DesignatedPrimary               *bool    `json:"designatedPrimary,omitempty"`
+	Priority                        *int     `json:"priority,omitempty"`
+	QuorumOverride                  *int     `json:"quorumOverride,omitempty"`
+	PermittedNodes                  []string `json:"permittedNodes,omitempty"`
+	Role                            *string  `json:"role,omitempty"`
+}
+
+// VirtualHost ...
+type VirtualHost struct {
+	ConfiguredObject
+	StorePath                              *string                  `json:"storePath,omitempty"`
+	ConnectionURL                          *string                  `json:"connectionUrl,omitempty"`
+	ConnectionPoolType                     *string                  `json:"connectionPoolType,omitempty"`
+	Username                               *string                  `json:"username,omitempty"`
+	Password                               *string                  `json:"password,omitempty"`
+	TableNamePrefix                        *string                  `json:"tableNamePrefix,omitempty"`
+	LocalTransactionSynchronizationPolicy  *string                  `json:"localTransactionSynchronizationPolicy,omitempty"`
+	RemoteTransactionSynchronizationPolicy *string                  `json:"remoteTransactionSynchronizationPolicy,omitempty"`
+	CoalescingSync                         *bool                    `json:"coalescingSync,omitempty"`
+	Durability                             *string                  `json:"durability,omitempty"`
+	StoreUnderfullSize                     *int64                   `json:"storeUnderfullSize,omitempty"`
+	StoreOverfullSize                      *int64                   `json:"storeOverfullSize,omitempty"`
+	StatisticsReportingPeriod              *int                     `json:"statisticsReportingPeriod,omitempty"`
+	StoreTransactionIdleTimeoutClose       *int64                   `json:"storeTransactionIdleTimeoutClose,omitempty"`
+	StoreTransactionIdleTimeoutWarn        *int64                   `json:"storeTransactionIdleTimeoutWarn,omitempty"`
+	StoreTransactionOpenTimeoutClose       *int64                   `json:"storeTransactionOpenTimeoutClose,omitempty"`
+	StoreTransactionOpenTimeoutWarn        *int64                   `json:"storeTransactionOpenTimeoutWarn,omitempty"`
+	HousekeepingCheckPeriod                *int64                   `json:"housekeepingCheckPeriod,omitempty"`
+	HousekeepingThreadCount                *int                     `json:"housekeepingThreadCount,omitempty"`
+	ConnectionThreadPoolSize               *int                     `json:"connectionThreadPoolSize,omitempty"`
+	NumberOfSelectors                      *int                     `json:"numberOfSelectors,omitempty"`
+	EnabledConnectionValidators            []string                 `json:"enabledConnectionValidators,omitempty"`
+	DisabledConnectionValidators           []string                 `json:"disabledConnectionValidators,omitempty"`
+	GlobalAddressDomains                   []string                 `json:"globalAddressDomains,omitempty"`
+	NodeAutoCreationPolicies               []NodeAutoCreationPolicy `json:"nodeAutoCreationPolicies,omitempty"`
+}
+
+// NodeAutoCreationPolicy defines the queue or exchange created on publishing or consuming from non-existing address
+type NodeAutoCreationPolicy struct {
+	Pattern          *string                `json:"pattern,omitempty"`
+	CreatedOnPublish *bool                  `json:"createdOnPublish,omitempty"`
+	CreatedOnConsume *bool                  `json:"createdOnConsume,omitempty"`
+	NodeType         *string                `json:"nodeType,omitempty"`
+	Attributes       map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// AlternateBinding defines the destination of messages which cannot be routed or delivered
+type AlternateBinding struct {
+	Destination *string           `json:"destination,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+// Queue ...
+type Queue struct {
+	ConfiguredObject
+	AlternateBinding                 *AlternateBinding `json:"alternateBinding,omitempty"`
+	Exclusive                        *string           `json:"exclusive,omitempty"`
+	EnsureNondestructiveConsumers    *bool             `json:"ensureNondestructiveConsumers,omitempty"`
+	NoLocal                          *bool             `json:"noLocal,omitempty"`
+	MessageGroupKeyOverride          *string           `json:"messageGroupKeyOverride,omitempty"`
+	MessageGroupDefaultGroup         *string           `json:"messageGroupDefaultGroup,omitempty"`
+	MaximumDistinctGroups            *int              `json:"maximumDistinctGroups,omitempty"`
+	MessageGroupType                 *string           `json:"messageGroupType,omitempty"`
+	MaximumDeliveryAttempts          *int              `json:"maximumDeliveryAttempts,omitempty"`
+	AlertThresholdMessageAge         *int64            `json:"alertThresholdMessageAge,omitempty"`
+	AlertThresholdMessageSize        *int64            `json:"alertThresholdMessageSize,omitempty"`
+	AlertThresholdQueueDepthBytes    *int64            `json:"alertThresholdQueueDepthBytes,omitempty"`
+	AlertThresholdQueueDepthMessages *int64            `json:"alertThresholdQueueDepthMessages,omitempty"`
+	AlertRepeatGap                   *int64            `json:"alertRepeatGap,omitempty"`
+	MessageDurability                *string           `json:"messageDurability,omitempty"`
+	MinimumMessageTTL                *int64            `json:"minimumMessageTtl,omitempty"`
+	MaximumMessageTTL                *int64            `json:"maximumMessageTtl,omitempty"`
+	DefaultFilters                   interface{}       `json:"defaultFilters,omitempty"`
+	HoldOnPublishEnabled             *bool             `json:"holdOnPublishEnabled,omitempty"`
+	MaximumQueueDepthBytes           *int64            `json:"maximumQueueDepthBytes,omitempty"`
+	MaximumQueueDepthMessages        *int64            `json:"maximumQueueDepthMessages,omitempty"`
+	OverflowPolicy                   *string           `json:"overflowPolicy,omitempty"`
+	ExpiryPolicy                     *string           `json:"expiryPolicy,omitempty"`
+	LvqKey                           *string           `json:"lvqKey,omitempty"`
+	Priorities                       *int              `json:"priorities,omitempty"`
+	SortKey                          *string           `json:"sortKey,omitempty"`
+}
+
+// Exchange ...
+type Exchange struct {
+	ConfiguredObject
+	AlternateBinding           *AlternateBinding `json:"alternateBinding,omitempty"`
+	UnroutableMessageBehaviour *string           `json:"unroutableMessageBehaviour,omitempty"`
+	Bindings                   []Binding         `json:"bindings,omitempty"`
+}
+
+// Binding of exchange to destination
+type Binding struct {
+	Name        *string                `json:"name,omitempty"`
+	BindingKey  *string                `json:"bindingKey,omitempty"`
+	Destination *string                `json:"destination,omitempty"`
+	Arguments   map[string]interface{} `json:"arguments,omitempty"`
+	Type        *string                `json:"type,omitempty"`
+}
+
+// Port ...
+type Port struct {
+	ConfiguredObject
+	Port                                          *int     `json:"port,omitempty"`
+	AllowConfidentialOperationsOnInsecureChannels *bool    `json:"allowConfidentialOperationsOnInsecureChannels,omitempty"`
+	Protocols                                     []string `json:"protocols,omitempty"`
+	Transports                                    []string `json:"transports,omitempty"`
+	BindingAddress                                *string  `json:"bindingAddress,omitempty"`
+	AuthenticationProvider                        *string  `json:"authenticationProvider,omitempty"`
+	KeyStore                                      *string  `json:"keyStore,omitempty"`
+	TrustStores                                   []string `json:"trustStores,omitempty"`
+	NeedClientAuth                                *bool    `json:"needClientAuth,omitempty"`
+	WantClientAuth                                *bool    `json:"wantClientAuth,omitempty"`
+	ClientCertRecorder                            *string  `json:"clientCertRecorder,omitempty"`
+	TCPNoDelay                                    *bool    `json:"tcpNoDelay,omitempty"`
+	ThreadPoolSize                                *int     `json:"threadPoolSize,omitempty"`
+	NumberOfSelectors                             *int     `json:"numberOfSelectors,omitempty"`
+	MaxOpenConnections                            *int     `json:"maxOpenConnections,omitempty"`
+	ThreadPoolMaximum                             *int     `json:"threadPoolMaximum,omitempty"`
+	ThreadPoolMinimum                             *int     `json:"threadPoolMinimum,omitempty"`
+	ManageBrokerOnNoAliasMatch                    *bool    `json:"manageBrokerOnNoAliasMatch,omitempty"`
+	BoundPort                                     *int     `json:"boundPort,omitempty"`
+}
+
+// VirtualHostAlias ...
+type VirtualHostAlias struct {
+	ConfiguredObject
+	Priority           *int    `json:"priority,omitempty"`
+	Pattern            *string `json:"pattern,omitempty"`
+	SystemAddressSpace *string `json:"systemAddressSpace,omitempty"`
+}
+
+// KeyStore ...
+type KeyStore struct {
+	ConfiguredObject
+	StoreURL                   *string `json:"storeUrl,omitempty"`
+	CertificateAlias           *string `json:"certificateAlias,omitempty"`
+	KeyManagerFactoryAlgorithm *string `json:"keyManagerFactoryAlgorithm,omitempty"`
+	KeyStoreType               *string `json:"keyStoreType,omitempty"`
+	Password                   *string `json:"password,omitempty"`
+	UseHostNameMatching        *bool   `json:"useHostNameMatching,omitempty"`
+	PrivateKeyURL              *string `json:"privateKeyUrl,omitempty"`
+	CertificateURL             *string `json:"certificateUrl,omitempty"`
+	IntermediateCertificateURL *string `json:"intermediateCertificateUrl,omitempty"`
+	KeyAlgorithm               *string `json:"keyAlgorithm,omitempty"`
+	SignatureAlgorithm         *string `json:"signatureAlgorithm,omitempty"`
+	KeyLength                  *int    `json:"keyLength,omitempty"`
+	DurationInMonths           *int    `json:"durationInMonths,omitempty"`
+}
+
+// TrustStore ...
+type TrustStore struct {
+	ConfiguredObject
+	TrustAnchorValidityEnforced           *bool    `json:"trustAnchorValidityEnforced,omitempty"`
+	ExposedAsMessageSource                *bool    `json:"exposedAsMessageSource,omitempty"`
+	IncludedVirtualHostNodeMessageSources []string `json:"includedVirtualHostNodeMessageSources,omitempty"`
+	ExcludedVirtualHostNodeMessageSources []string `json:"excludedVirtualHostNodeMessageSources,omitempty"`
+	StoreURL                              *string  `json:"storeUrl,omitempty"`
+	TrustManagerFactoryAlgorithm          *string  `json:"trustManagerFactoryAlgorithm,omitempty"`
+	TrustStoreType                        *string  `json:"trustStoreType,omitempty"`
+	Password                              *string  `json:"password,omitempty"`
+	PeersOnly                             *bool    `json:"peersOnly,omitempty"`
+	CertificatesURL                       *string  `json:"certificatesUrl,omitempty"`
+	SiteURL                               *string  `json:"siteUrl,omitempty"`
+}
+
+// AuthenticationProvider ...
+type AuthenticationProvider struct {
+	ConfiguredObject
+	SecureOnlyMechanisms []string `json:"secureOnlyMechanisms,omitempty"`
+	DisabledMechanisms   []string `json:"disabledMechanisms,omitempty"`
+	Path                 *string  `json:"path,omitempty"`
+	ProviderURL          *string  `json:"providerUrl,omitempty"`
+	ProviderAuthURL      *string  `json:"providerAuthUrl,omitempty"`
+	SearchContext        *string  `json:"searchContext,omitempty"`
+	SearchFilter         *string  `json:"searchFilter,omitempty"`
+	BindWithoutSearch    *bool    `json:"bindWithoutSearch,omitempty"`
+	SearchUsername       *string  `json:"searchUsername,omitempty"`
+	SearchPassword       *string  `json:"searchPassword,omitempty"`
+	TrustStore           *string  `json:"trustStore,omitempty"`
+	ClientID             *string  `json:"clientId,omitempty"`
+	ClientSecret         *string  `json:"clientSecret,omitempty"`
+	Scope                *string  `json:"scope,omitempty"`
+}
+
+// User ...
+type User struct {
+	ConfiguredObject
+	Password *string `json:"password,omitempty"`
+}
+
+// GroupProvider ...
+type GroupProvider struct {
+	ConfiguredObject
+	Path                            *string           `json:"path,omitempty"`
+	CloudFoundryEndpointURI         *string           `json:"cloudFoundryEndpointURI,omitempty"`
+	TrustStore                      *string           `json:"trustStore,omitempty"`
+	ServiceToManagementGroupMapping map[string]string `json:"serviceToManagementGroupMapping,omitempty"`
+}
+
+// Group ...
+type Group struct {
+	ConfiguredObject
+}
+
+// GroupMember ...
+type GroupMember struct {
+	ConfiguredObject
+}
+
+// AccessControlProvider ...
+type AccessControlProvider struct {
+	ConfiguredObject
+	Priority      *int    `json:"priority,omitempty"`
+	Path          *string `json:"path,omitempty"`
+	DefaultResult *string `json:"defaultResult,omitempty"`
+}
+
+// String returns pointer to given value
+func String(v string) *string {
+	return &v
+}
+
+// Bool returns pointer to given value
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns pointer to given value
+func Int(v int) *int {
+	return &v
+}
+
+// Int64 returns pointer to given value
+func Int64(v int64) *int64 {
+	return &v
+}
+
+// StringValue returns the value of given pointer or empty string for nil pointer
+func StringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
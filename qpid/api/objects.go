@@ -0,0 +1,500 @@
+package api
+
+// GetVirtualHostNode returns virtual host node with given name
+func (c *Client) GetVirtualHostNode(name string) (*VirtualHostNode, error) {
+	object := &VirtualHostNode{}
+	err := c.get("virtualhostnode", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListVirtualHostNodes returns all virtual host nodes
+func (c *Client) ListVirtualHostNodes() ([]VirtualHostNode, error) {
+	objects := []VirtualHostNode{}
+	err := c.list("virtualhostnode", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateVirtualHostNode creates given virtual host node
+func (c *Client) CreateVirtualHostNode(object *VirtualHostNode) error {
+	return c.create("virtualhostnode", nil, object)
+}
+
+// UpdateVirtualHostNode changes the attributes of virtual host node with given name to the ones set in given object
+func (c *Client) UpdateVirtualHostNode(name string, object *VirtualHostNode) error {
+	return c.update("virtualhostnode", []string{name}, object)
+}
+
+// DeleteVirtualHostNode deletes virtual host node with given name
+func (c *Client) DeleteVirtualHostNode(name string) error {
+	return c.delete("virtualhostnode", []string{name})
+}
+
+// GetVirtualHost returns virtual host with given name
+func (c *Client) GetVirtualHost(node string, name string) (*VirtualHost, error) {
+	object := &VirtualHost{}
+	err := c.get("virtualhost", []string{node, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListVirtualHosts returns all virtual hosts of given parent
+func (c *Client) ListVirtualHosts(node string) ([]VirtualHost, error) {
+	objects := []VirtualHost{}
+	err := c.list("virtualhost", []string{node}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateVirtualHost creates given virtual host
+func (c *Client) CreateVirtualHost(node string, object *VirtualHost) error {
+	return c.create("virtualhost", []string{node}, object)
+}
+
+// UpdateVirtualHost changes the attributes of virtual host with given name to the ones set in given object
+func (c *Client) UpdateVirtualHost(node string, name string, object *VirtualHost) error {
+	return c.update("virtualhost", []string{node, name}, object)
+}
+
+// DeleteVirtualHost deletes virtual host with given name
+func (c *Client) DeleteVirtualHost(node string, name string) error {
+	return c.delete("virtualhost", []string{node, name})
+}
+
+// GetQueue returns queue with given name
+func (c *Client) GetQueue(node string, host string, name string) (*Queue, error) {
+	object := &Queue{}
+	err := c.get("queue", []string{node, host, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListQueues returns all queues of given parent
+func (c *Client) ListQueues(node string, host string) ([]Queue, error) {
+	objects := []Queue{}
+	err := c.list("queue", []string{node, host}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateQueue creates given queue
+func (c *Client) CreateQueue(node string, host string, object *Queue) error {
+	return c.create("queue", []string{node, host}, object)
+}
+
+// UpdateQueue changes the attributes of queue with given name to the ones set in given object
+func (c *Client) UpdateQueue(node string, host string, name string, object *Queue) error {
+	return c.update("queue", []string{node, host, name}, object)
+}
+
+// DeleteQueue deletes queue with given name
+func (c *Client) DeleteQueue(node string, host string, name string) error {
+	return c.delete("queue", []string{node, host, name})
+}
+
+// GetExchange returns exchange with given name
+func (c *Client) GetExchange(node string, host string, name string) (*Exchange, error) {
+	object := &Exchange{}
+	err := c.get("exchange", []string{node, host, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListExchanges returns all exchanges of given parent
+func (c *Client) ListExchanges(node string, host string) ([]Exchange, error) {
+	objects := []Exchange{}
+	err := c.list("exchange", []string{node, host}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateExchange creates given exchange
+func (c *Client) CreateExchange(node string, host string, object *Exchange) error {
+	return c.create("exchange", []string{node, host}, object)
+}
+
+// UpdateExchange changes the attributes of exchange with given name to the ones set in given object
+func (c *Client) UpdateExchange(node string, host string, name string, object *Exchange) error {
+	return c.update("exchange", []string{node, host, name}, object)
+}
+
+// DeleteExchange deletes exchange with given name
+func (c *Client) DeleteExchange(node string, host string, name string) error {
+	return c.delete("exchange", []string{node, host, name})
+}
+
+// GetPort returns port with given name
+func (c *Client) GetPort(name string) (*Port, error) {
+	object := &Port{}
+	err := c.get("port", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListPorts returns all ports
+func (c *Client) ListPorts() ([]Port, error) {
+	objects := []Port{}
+	err := c.list("port", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreatePort creates given port
+func (c *Client) CreatePort(object *Port) error {
+	return c.create("port", nil, object)
+}
+
+// UpdatePort changes the attributes of port with given name to the ones set in given object
+func (c *Client) UpdatePort(name string, object *Port) error {
+	return c.update("port", []string{name}, object)
+}
+
+// DeletePort deletes port with given name
+func (c *Client) DeletePort(name string) error {
+	return c.delete("port", []string{name})
+}
+
+// GetVirtualHostAlias returns virtual host alias with given name
+func (c *Client) GetVirtualHostAlias(port string, name string) (*VirtualHostAlias, error) {
+	object := &VirtualHostAlias{}
+	err := c.get("virtualhostalias", []string{port, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListVirtualHostAliases returns all virtual host aliases of given parent
+func (c *Client) ListVirtualHostAliases(port string) ([]VirtualHostAlias, error) {
+	objects := []VirtualHostAlias{}
+	err := c.list("virtualhostalias", []string{port}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateVirtualHostAlias creates given virtual host alias
+func (c *Client) CreateVirtualHostAlias(port string, object *VirtualHostAlias) error {
+	return c.create("virtualhostalias", []string{port}, object)
+}
+
+// UpdateVirtualHostAlias changes the attributes of virtual host alias with given name to the ones set in given object
+func (c *Client) UpdateVirtualHostAlias(port string, name string, object *VirtualHostAlias) error {
+	return c.update("virtualhostalias", []string{port, name}, object)
+}
+
+// DeleteVirtualHostAlias deletes virtual host alias with given name
+func (c *Client) DeleteVirtualHostAlias(port string, name string) error {
+	return c.delete("virtualhostalias", []string{port, name})
+}
+
+// GetKeyStore returns key store with given name
+func (c *Client) GetKeyStore(name string) (*KeyStore, error) {
+	object := &KeyStore{}
+	err := c.get("keystore", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListKeyStores returns all key stores
+func (c *Client) ListKeyStores() ([]KeyStore, error) {
+	objects := []KeyStore{}
+	err := c.list("keystore", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateKeyStore creates given key store
+func (c *Client) CreateKeyStore(object *KeyStore) error {
+	return c.create("keystore", nil, object)
+}
+
+// UpdateKeyStore changes the attributes of key store with given name to the ones set in given object
+func (c *Client) UpdateKeyStore(name string, object *KeyStore) error {
+	return c.update("keystore", []string{name}, object)
+}
+
+// DeleteKeyStore deletes key store with given name
+func (c *Client) DeleteKeyStore(name string) error {
+	return c.delete("keystore", []string{name})
+}
+
+// GetTrustStore returns trust store with given name
+func (c *Client) GetTrustStore(name string) (*TrustStore, error) {
+	object := &TrustStore{}
+	err := c.get("truststore", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListTrustStores returns all trust stores
+func (c *Client) ListTrustStores() ([]TrustStore, error) {
+	objects := []TrustStore{}
+	err := c.list("truststore", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateTrustStore creates given trust store
+func (c *Client) CreateTrustStore(object *TrustStore) error {
+	return c.create("truststore", nil, object)
+}
+
+// UpdateTrustStore changes the attributes of trust store with given name to the ones set in given object
+func (c *Client) UpdateTrustStore(name string, object *TrustStore) error {
+	return c.update("truststore", []string{name}, object)
+}
+
+// DeleteTrustStore deletes trust store with given name
+func (c *Client) DeleteTrustStore(name string) error {
+	return c.delete("truststore", []string{name})
+}
+
+// GetAuthenticationProvider returns authentication provider with given name
+func (c *Client) GetAuthenticationProvider(name string) (*AuthenticationProvider, error) {
+	object := &AuthenticationProvider{}
+	err := c.get("authenticationprovider", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListAuthenticationProviders returns all authentication providers
+func (c *Client) ListAuthenticationProviders() ([]AuthenticationProvider, error) {
+	objects := []AuthenticationProvider{}
+	err := c.list("authenticationprovider", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateAuthenticationProvider creates given authentication provider
+func (c *Client) CreateAuthenticationProvider(object *AuthenticationProvider) error {
+	return c.create("authenticationprovider", nil, object)
+}
+
+// UpdateAuthenticationProvider changes the attributes of authentication provider with given name to the ones set in given object
+func (c *Client) UpdateAuthenticationProvider(name string, object *AuthenticationProvider) error {
+	return c.update("authenticationprovider", []string{name}, object)
+}
+
+// DeleteAuthenticationProvider deletes authentication provider with given name
+func (c *Client) DeleteAuthenticationProvider(name string) error {
+	return c.delete("authenticationprovider", []string{name})
+}
+
+// GetUser returns user with given name
+func (c *Client) GetUser(authenticationProvider string, name string) (*User, error) {
+	object := &User{}
+	err := c.get("user", []string{authenticationProvider, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListUsers returns all users of given parent
+func (c *Client) ListUsers(authenticationProvider string) ([]User, error) {
+	objects := []User{}
+	err := c.list("user", []string{authenticationProvider}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateUser creates given user
+func (c *Client) CreateUser(authenticationProvider string, object *User) error {
+	return c.create("user", []string{authenticationProvider}, object)
+}
+
+// UpdateUser changes the attributes of user with given name to the ones set in given object
+func (c *Client) UpdateUser(authenticationProvider string, name string, object *User) error {
+	return c.update("user", []string{authenticationProvider, name}, object)
+}
+
+// DeleteUser deletes user with given name
+func (c *Client) DeleteUser(authenticationProvider string, name string) error {
+	return c.delete("user", []string{authenticationProvider, name})
+}
+
+// GetGroupProvider returns group provider with given name
+func (c *Client) GetGroupProvider(name string) (*GroupProvider, error) {
+	object := &GroupProvider{}
+	err := c.get("groupprovider", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListGroupProviders returns all group providers
+func (c *Client) ListGroupProviders() ([]GroupProvider, error) {
+	objects := []GroupProvider{}
+	err := c.list("groupprovider", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateGroupProvider creates given group provider
+func (c *Client) CreateGroupProvider(object *GroupProvider) error {
+	return c.create("groupprovider", nil, object)
+}
+
+// UpdateGroupProvider changes the attributes of group provider with given name to the ones set in given object
+func (c *Client) UpdateGroupProvider(name string, object *GroupProvider) error {
+	return c.update("groupprovider", []string{name}, object)
+}
+
+// DeleteGroupProvider deletes group provider with given name
+func (c *Client) DeleteGroupProvider(name string) error {
+	return c.delete("groupprovider", []string{name})
+}
+
+// GetGroup returns group with given name
+func (c *Client) GetGroup(groupProvider string, name string) (*Group, error) {
+	object := &Group{}
+	err := c.get("group", []string{groupProvider, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListGroups returns all groups of given parent
+func (c *Client) ListGroups(groupProvider string) ([]Group, error) {
+	objects := []Group{}
+	err := c.list("group", []string{groupProvider}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateGroup creates given group
+func (c *Client) CreateGroup(groupProvider string, object *Group) error {
+	return c.create("group", []string{groupProvider}, object)
+}
+
+// UpdateGroup changes the attributes of group with given name to the ones set in given object
+func (c *Client) UpdateGroup(groupProvider string, name string, object *Group) error {
+	return c.update("group", []string{groupProvider, name}, object)
+}
+
+// DeleteGroup deletes group with given name
+func (c *Client) DeleteGroup(groupProvider string, name string) error {
+	return c.delete("group", []string{groupProvider, name})
+}
+
+// GetGroupMember returns group member with given name
+func (c *Client) GetGroupMember(groupProvider string, group string, name string) (*GroupMember, error) {
+	object := &GroupMember{}
+	err := c.get("groupmember", []string{groupProvider, group, name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListGroupMembers returns all group members of given parent
+func (c *Client) ListGroupMembers(groupProvider string, group string) ([]GroupMember, error) {
+	objects := []GroupMember{}
+	err := c.list("groupmember", []string{groupProvider, group}, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateGroupMember creates given group member
+func (c *Client) CreateGroupMember(groupProvider string, group string, object *GroupMember) error {
+	return c.create("groupmember", []string{groupProvider, group}, object)
+}
+
+// UpdateGroupMember changes the attributes of group member with given name to the ones set in given object
+func (c *Client) UpdateGroupMember(groupProvider string, group string, name string, object *GroupMember) error {
+	return c.update("groupmember", []string{groupProvider, group, name}, object)
+}
+
+// DeleteGroupMember deletes group member with given name
+func (c *Client) DeleteGroupMember(groupProvider string, group string, name string) error {
+	return c.delete("groupmember", []string{groupProvider, group, name})
+}
+
+// GetAccessControlProvider returns access control provider with given name
+func (c *Client) GetAccessControlProvider(name string) (*AccessControlProvider, error) {
+	object := &AccessControlProvider{}
+	err := c.get("accesscontrolprovider", []string{name}, object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// ListAccessControlProviders returns all access control providers
+func (c *Client) ListAccessControlProviders() ([]AccessControlProvider, error) {
+	objects := []AccessControlProvider{}
+	err := c.list("accesscontrolprovider", nil, &objects)
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// CreateAccessControlProvider creates given access control provider
+func (c *Client) CreateAccessControlProvider(object *AccessControlProvider) error {
+	return c.create("accesscontrolprovider", nil, object)
+}
+
+// UpdateAccessControlProvider changes the attributes of access control provider with given name to the ones set in given object
+func (c *Client) UpdateAccessControlProvider(name string, object *AccessControlProvider) error {
+	return c.update("accesscontrolprovider", []string{name}, object)
+}
+
+// DeleteAccessControlProvider deletes access control provider with given name
+func (c *Client) DeleteAccessControlProvider(name string) error {
+	return c.delete("accesscontrolprovider", []string{name})
+}
+
+// ListBindings returns bindings of exchange with given name
+func (c *Client) ListBindings(node string, host string, exchange string) ([]Binding, error) {
+	object, err := c.GetExchange(node, host, exchange)
+	if err != nil {
+		return nil, err
+	}
+	return object.Bindings, nil
+}
@@ -0,0 +1,21 @@
+package qpid
+
+import (
+	"github.com/terraform-providers/terraform-provider-qpid/qpid/api"
+	"net/http"
+)
+
+// ErrNotFound is matched by errors reporting that the requested broker object does not exist
+var ErrNotFound = api.ErrNotFound
+
+// QpidAPIError represents an error response of the broker management API
+type QpidAPIError = api.QpidAPIError
+
+// IsNotFound returns true if the error reports that the requested broker object does not exist
+func IsNotFound(err error) bool {
+	return api.IsNotFound(err)
+}
+
+func newQpidAPIError(res *http.Response) *QpidAPIError {
+	return api.NewQpidAPIError(res)
+}
@@ -0,0 +1,92 @@
+package qpid
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func newErrorResponse(status int, body string) *http.Response {
+	u, _ := url.Parse("http://localhost:8080/api/latest/queue/node/host/queue")
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: http.MethodGet, URL: u},
+	}
+}
+
+func TestConvertHttpResponseToMapErrors(t *testing.T) {
+	responses := []struct {
+		status   int
+		body     string
+		notFound bool
+		message  string
+	}{
+		{status: http.StatusNotFound, body: `{"errorMessage": "Queue 'queue' not found"}`, notFound: true, message: "Queue 'queue' not found"},
+		{status: http.StatusInternalServerError, body: `{"errorMessage": "Internal error"}`, message: "Internal error"},
+		{status: http.StatusUnprocessableEntity, body: "Invalid attribute value", message: "Invalid attribute value"},
+		{status: http.StatusServiceUnavailable, body: ""},
+	}
+
+	for _, r := range responses {
+		attributes, err := convertHttpResponseToMap(newErrorResponse(r.status, r.body))
+		apiError, ok := err.(*QpidAPIError)
+		if !ok {
+			t.Fatalf("unexpected error %v for status %d", err, r.status)
+		}
+		if apiError.StatusCode != r.status || apiError.Message != r.message || apiError.Path != "/api/latest/queue/node/host/queue" {
+			t.Errorf("unexpected error %+v for status %d", apiError, r.status)
+		}
+		if IsNotFound(err) != r.notFound || IsNotFound(fmt.Errorf("wrapped: %w", err)) != r.notFound {
+			t.Errorf("unexpected not found result for status %d", r.status)
+		}
+		if len(*attributes) != 0 {
+			t.Errorf("unexpected attributes %v for status %d", *attributes, r.status)
+		}
+	}
+}
+
+func TestResourceReadOnBrokerError(t *testing.T) {
+	status := int32(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeFakeBrokerError(w, int(atomic.LoadInt32(&status)), "broker error")
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "admin", "admin", "v7.1", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	raw := map[string]interface{}{"name": "queue", "virtual_host_node": "node", "virtual_host": "host"}
+
+	// broker error does not mark the queue as gone
+	d := schema.TestResourceDataRaw(t, resourceQueue().Schema, raw)
+	d.SetId("id")
+	err = readQueue(d, client)
+	if err == nil || IsNotFound(err) || !strings.Contains(err.Error(), "broker error") || d.Id() != "id" {
+		t.Errorf("unexpected result of reading queue on broker error, id '%s': %v", d.Id(), err)
+	}
+	exists, err := existsQueue(d, client)
+	if err == nil || exists {
+		t.Errorf("unexpected result of queue existence check on broker error %v: %v", exists, err)
+	}
+
+	// only not found response marks the queue as gone
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	err = readQueue(d, client)
+	if err != nil || d.Id() != "" {
+		t.Errorf("unexpected result of reading missing queue, id '%s': %v", d.Id(), err)
+	}
+	exists, err = existsQueue(d, client)
+	if err != nil || exists {
+		t.Errorf("unexpected result of missing queue existence check %v: %v", exists, err)
+	}
+}
@@ -0,0 +1,189 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/terraform-providers/terraform-provider-qpid/qpid/api"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// metadataServicePath is the full path of the broker service describing the supported categories, types and attributes
+const metadataServicePath = "/service/metadata"
+
+// BrokerCapabilities holds the broker product version and the types and attributes supported by the broker
+type BrokerCapabilities struct {
+	ProductVersion string
+
+	// types holds metadata of the supported types by lower cased category name
+	types map[string]map[string]*typeMetadata
+}
+
+type typeMetadata struct {
+	Attributes map[string]*attributeMetadata `json:"attributes"`
+}
+
+type attributeMetadata struct {
+	Type        string        `json:"type"`
+	ValidValues []interface{} `json:"validValues"`
+}
+
+// DiscoverCapabilities fetches the broker product version and the metadata of the supported categories and types.
+// The metadata are not available on all brokers, in that case, the types and attributes are not verified.
+func (c *Client) DiscoverCapabilities() error {
+	broker, err := c.GetBroker()
+	if err != nil {
+		return err
+	}
+
+	capabilities := &BrokerCapabilities{}
+	if productVersion, ok := (*broker)["productVersion"]; ok {
+		capabilities.ProductVersion = fmt.Sprintf("%v", productVersion)
+	}
+
+	metadata, err := c.getMetadata()
+	if err != nil {
+		log.Printf("[WARN] Qpid: broker %s does not provide metadata, types and attributes are not verified: %v", capabilities.ProductVersion, err)
+	} else {
+		capabilities.types = make(map[string]map[string]*typeMetadata, len(*metadata))
+		for category, types := range *metadata {
+			capabilities.types[strings.ToLower(category)] = types
+		}
+	}
+
+	log.Printf("[INFO] Qpid: broker product version %s", capabilities.ProductVersion)
+	c.capabilities = capabilities
+	return nil
+}
+
+// Capabilities returns the capabilities of the broker discovered at configure time, nil if not discovered
+func (c *Client) Capabilities() *BrokerCapabilities {
+	return c.capabilities
+}
+
+func (c *Client) getMetadata() (*map[string]map[string]*typeMetadata, error) {
+	attributes, err := c.restClient.GetAsMap(metadataServicePath, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]map[string]*typeMetadata{}
+	err = api.Decode(attributes, &metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// SupportsType returns true when the broker supports the type of given category.
+// The types of categories without metadata are considered supported.
+func (b *BrokerCapabilities) SupportsType(category string, objectType string) bool {
+	types, known := b.types[category]
+	if !known {
+		return true
+	}
+	_, supported := types[objectType]
+	return supported
+}
+
+// SupportedTypes returns sorted names of the supported types of given category
+func (b *BrokerCapabilities) SupportedTypes(category string) []string {
+	names := make([]string, 0, len(b.types[category]))
+	for name := range b.types[category] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SupportsAttribute returns true when the broker supports the attribute of the object with given category and type.
+// The attributes of the types without metadata are considered supported, as well as the attributes not known
+// to any type of the category, the provider cannot tell them apart from the schema keys which are not broker attributes.
+func (b *BrokerCapabilities) SupportsAttribute(category string, objectType string, attribute string) bool {
+	metadata, known := b.types[category][objectType]
+	if !known || metadata.Attributes == nil || !b.knowsAttribute(category, attribute) {
+		return true
+	}
+	_, supported := metadata.Attributes[attribute]
+	return supported
+}
+
+// knowsAttribute returns true when the metadata of any type of given category describe the attribute
+func (b *BrokerCapabilities) knowsAttribute(category string, attribute string) bool {
+	for _, metadata := range b.types[category] {
+		if _, known := metadata.Attributes[attribute]; known {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsValue returns true when the value is one of the valid values of the attribute,
+// attributes without restricted values accept any value.
+func (b *BrokerCapabilities) SupportsValue(category string, objectType string, attribute string, value string) bool {
+	validValues := b.validValues(category, objectType, attribute)
+	if len(validValues) == 0 {
+		return true
+	}
+	for _, validValue := range validValues {
+		if validValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BrokerCapabilities) validValues(category string, objectType string, attribute string) []string {
+	metadata, known := b.types[category][objectType]
+	if !known || metadata.Attributes[attribute] == nil {
+		return nil
+	}
+	items := metadata.Attributes[attribute].ValidValues
+	return *convertToArrayOfStrings(&items)
+}
+
+// verifyBrokerCapabilities returns CustomizeDiffFunc failing the plan when the type of the object with given category,
+// or its configured attribute or value is known to be unsupported by the broker. Excluded keys are not broker attributes,
+// keys not mapping to attributes known to the broker metadata are not verified.
+func verifyBrokerCapabilities(category string, resource func() *schema.Resource, exclude ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		client, ok := meta.(*Client)
+		if !ok || client.Capabilities() == nil {
+			return nil
+		}
+		capabilities := client.Capabilities()
+
+		objectType := d.Get("type").(string)
+		if objectType == "" {
+			return nil
+		}
+		if (d.Id() == "" || d.HasChange("type")) && !capabilities.SupportsType(category, objectType) {
+			return fmt.Errorf("%s type '%s' is not supported by broker %s, supported types: %v",
+				category, objectType, capabilities.ProductVersion, capabilities.SupportedTypes(category))
+		}
+
+		excludes := arrayOfStringsToMap(append(exclude, "name", "type"))
+		for key, s := range resource().Schema {
+			if _, excluded := excludes[key]; excluded {
+				continue
+			}
+			value, set := d.GetOk(key)
+			if !set || (d.Id() != "" && !d.HasChange(key)) {
+				continue
+			}
+
+			attribute := convertToCamelCase(key)
+			if !capabilities.SupportsAttribute(category, objectType, attribute) {
+				return fmt.Errorf("attribute '%s' of %s type '%s' is not supported by broker %s",
+					key, category, objectType, capabilities.ProductVersion)
+			}
+			if s.Type == schema.TypeString && !capabilities.SupportsValue(category, objectType, attribute, value.(string)) {
+				return fmt.Errorf("value '%s' of attribute '%s' of %s type '%s' is not supported by broker %s, supported values: %v",
+					value, key, category, objectType, capabilities.ProductVersion, capabilities.validValues(category, objectType, attribute))
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"regexp"
+	"testing"
+)
+
+// fakeBrokerQueueMetadata describes queue types of a broker without REJECT overflow policy and lvq queues
+var fakeBrokerQueueMetadata = map[string]interface{}{
+	"Queue": map[string]interface{}{
+		"standard": map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"name":                   map[string]interface{}{"type": "String"},
+				"durable":                map[string]interface{}{"type": "Boolean"},
+				"maximumQueueDepthBytes": map[string]interface{}{"type": "Long"},
+				"overflowPolicy": map[string]interface{}{
+					"type":        "OverflowPolicy",
+					"validValues": []interface{}{"NONE", "RING", "PRODUCER_FLOW_CONTROL", "FLOW_TO_DISK"},
+				},
+			},
+		},
+		"priority": map[string]interface{}{
+			"attributes": map[string]interface{}{
+				"name":       map[string]interface{}{"type": "String"},
+				"priorities": map[string]interface{}{"type": "Integer"},
+			},
+		},
+	},
+}
+
+func newFakeBrokerClientWithMetadata(t *testing.T, metadata map[string]interface{}) (*Client, *fakeBroker) {
+	client, broker := newFakeBrokerClient(t)
+	broker.metadata = metadata
+	err := client.DiscoverCapabilities()
+	if err != nil {
+		broker.Close()
+		t.Fatalf("unable to discover broker capabilities: %v", err)
+	}
+	return client, broker
+}
+
+func TestClientDiscoverCapabilities(t *testing.T) {
+	client, broker := newFakeBrokerClientWithMetadata(t, fakeBrokerQueueMetadata)
+	defer broker.Close()
+
+	capabilities := client.Capabilities()
+	if capabilities == nil || capabilities.ProductVersion == "" {
+		t.Fatalf("broker product version is not discovered: %v", capabilities)
+	}
+	if !capabilities.SupportsType("queue", "standard") || capabilities.SupportsType("queue", "lvq") {
+		t.Errorf("unexpected supported queue types %v", capabilities.SupportedTypes("queue"))
+	}
+	if !capabilities.SupportsType("exchange", "direct") {
+		t.Errorf("type of category without metadata is not supported")
+	}
+	if !capabilities.SupportsAttribute("queue", "standard", "overflowPolicy") || capabilities.SupportsAttribute("queue", "standard", "priorities") ||
+		!capabilities.SupportsAttribute("queue", "standard", "messageDurability") {
+		t.Errorf("unexpected supported queue attributes")
+	}
+	if !capabilities.SupportsValue("queue", "standard", "overflowPolicy", "RING") || capabilities.SupportsValue("queue", "standard", "overflowPolicy", "REJECT") {
+		t.Errorf("unexpected supported overflow policies")
+	}
+
+	broker.metadata = nil
+	err := client.DiscoverCapabilities()
+	if err != nil {
+		t.Fatalf("unable to discover capabilities of broker without metadata: %v", err)
+	}
+	if !client.Capabilities().SupportsType("queue", "lvq") {
+		t.Errorf("types are verified without metadata")
+	}
+}
+
+func TestBrokerCapabilitiesPlanVerification(t *testing.T) {
+	client, broker := newFakeBrokerClientWithMetadata(t, fakeBrokerQueueMetadata)
+	defer broker.Close()
+
+	provider := Provider().(*schema.Provider)
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return client, nil
+	}
+	providers := map[string]terraform.ResourceProvider{"qpid": provider}
+
+	queueConfig := func(attributes string) string {
+		return `
+resource "qpid_queue" "test" {
+    name = "test"
+    virtual_host_node = "node"
+    virtual_host = "host"
+` + attributes + `
+}`
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		Steps: []resource.TestStep{
+			{
+				Config:      queueConfig(`type = "lvq"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`queue type 'lvq' is not supported by broker`),
+			},
+			{
+				Config:      queueConfig("type = \"standard\"\npriorities = 5"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`attribute 'priorities' of queue type 'standard' is not supported by broker`),
+			},
+			{
+				Config:             queueConfig("type = \"standard\"\nmessage_durability = \"NEVER\""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:      queueConfig("type = \"standard\"\noverflow_policy = \"REJECT\""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value 'REJECT' of attribute 'overflow_policy' of queue type 'standard' is not supported`),
+			},
+		},
+	})
+}
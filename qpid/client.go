@@ -1,10 +1,11 @@
 package qpid
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
-	"strconv"
+	"sync"
 	"time"
 )
 
@@ -12,21 +13,39 @@ import (
 type Client struct {
 	modelVersion string
 	restClient   *SimpleRestClient
+	cache        *readCache
+	capabilities *BrokerCapabilities
+
+	// masters holds endpoint indexes of the brokers where the virtual host nodes are the masters
+	mastersMutex sync.Mutex
+	masters      map[string]int
 }
 
-// NewClient creates a Qpid client for given URI, credentials, model version and transport
+// NewClient creates a Qpid client for given URI, basic authentication credentials, model version and transport
 func NewClient(uri string, username string, password string, modelVersion string, transport http.RoundTripper) (me *Client, err error) {
+	return NewClientWithCredentials(uri, NewBasicAuthCredentials(username, password), modelVersion, transport)
+}
+
+// NewClientWithCredentials creates a Qpid client for given URI, credentials, model version and transport
+func NewClientWithCredentials(uri string, credentials *Credentials, modelVersion string, transport http.RoundTripper) (me *Client, err error) {
+	return NewClientForEndpoints([]string{uri}, credentials, modelVersion, transport)
+}
 
-	log.Printf("Qpid Client for endpoint: %s, model: %s, username %s", uri, modelVersion, username)
+// NewClientForEndpoints creates a Qpid client for given URIs of brokers from the same replication group,
+// credentials, model version and transport
+func NewClientForEndpoints(uris []string, credentials *Credentials, modelVersion string, transport http.RoundTripper) (me *Client, err error) {
 
-	credentials := NewBasicAuthCredentials(username, password)
-	restClient, err := NewSimpleRestClient(uri+"/api/"+modelVersion, credentials, transport)
+	log.Printf("Qpid Client for endpoints: %v, model: %s", uris, modelVersion)
+
+	restClient, err := NewSimpleRestClientForEndpoints(uris, credentials, transport)
 	if err != nil {
-		return &Client{}, nil
+		return &Client{}, err
 	}
+	restClient.SetBasePath(apiRootPath)
 	me = &Client{
 		restClient:   restClient,
 		modelVersion: modelVersion,
+		masters:      map[string]int{},
 	}
 
 	return me, nil
@@ -42,98 +61,94 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.restClient.SetTimeout(timeout)
 }
 
+// SetRetryPolicy ...
+func (c *Client) SetRetryPolicy(retryPolicy RetryPolicy) {
+	c.restClient.SetRetryPolicy(retryPolicy)
+}
+
+// SetRequestLimits sets the maximum number of concurrent requests and requests per second, zero means no limit
+func (c *Client) SetRequestLimits(maxConcurrentRequests int, requestsPerSecond float64) {
+	c.restClient.SetRequestLimits(maxConcurrentRequests, requestsPerSecond)
+}
+
 // CreateVirtualHostNode ...
 func (c *Client) CreateVirtualHostNode(attributes *map[string]interface{}) (res *http.Response, err error) {
-	return c.restClient.Post("virtualhostnode", attributes)
+	return c.Create("virtualhostnode", nil, attributes)
 }
 
 // GetVirtualHostNode ...
 func (c *Client) GetVirtualHostNode(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("virtualhostnode/" + url.PathEscape(name))
+	return c.Get("virtualhostnode", []string{name}, &QueryOptions{Actuals: true})
 }
 
 // DeleteVirtualHostNode ...
 func (c *Client) DeleteVirtualHostNode(name string) (res *http.Response, err error) {
-	return c.deleteConfiguredObject("virtualhostnode/" + url.PathEscape(name))
+	return c.Delete("virtualhostnode", []string{name})
 }
 
 // UpdateVirtualHostNode ...
 func (c *Client) UpdateVirtualHostNode(name string, attributes *map[string]interface{}) (res *http.Response, err error) {
-	return c.restClient.Post("virtualhostnode/"+url.PathEscape(name), attributes)
+	return c.Update("virtualhostnode", []string{name}, attributes)
 }
 
 // GetVirtualHost ...
 func (c *Client) GetVirtualHost(node string, host string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("virtualhost/" + url.PathEscape(node) + "/" + url.PathEscape(host))
+	return c.Get("virtualhost", []string{node, host}, &QueryOptions{Actuals: true})
 }
 
 // CreateVirtualHost ...
 func (c *Client) CreateVirtualHost(node string, attributes *map[string]interface{}) (res *http.Response, err error) {
-	return c.restClient.Post("virtualhost/"+url.PathEscape(node), attributes)
+	return c.Create("virtualhost", []string{node}, attributes)
 }
 
 // DeleteVirtualHost ...
 func (c *Client) DeleteVirtualHost(node string, host string) (res *http.Response, err error) {
-	return c.deleteConfiguredObject("virtualhost/" + url.PathEscape(node) + "/" + url.PathEscape(host))
+	return c.Delete("virtualhost", []string{node, host})
 }
 
 // UpdateVirtualHost
 func (c *Client) UpdateVirtualHost(node string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("virtualhost/"+url.PathEscape(node)+"/"+url.PathEscape(name), attributes)
-}
-
-func (c *Client) getConfiguredObject(path string) (*map[string]interface{}, error) {
-	return c.getConfiguredObjectAttributes(path, true)
-}
-
-func (c *Client) getConfiguredObjectAttributes(path string, actuals bool) (*map[string]interface{}, error) {
-	v := url.Values{}
-	v.Set("actuals", strconv.FormatBool(actuals))
-	return c.restClient.GetAsMap(path, v)
-}
-
-func (c *Client) deleteConfiguredObject(path string) (*http.Response, error) {
-	return c.restClient.Delete(path)
+	return c.Update("virtualhost", []string{node, name}, attributes)
 }
 
 // CreateQueue ...
 func (c *Client) CreateQueue(node string, host string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("queue/"+url.PathEscape(node)+"/"+url.PathEscape(host), attributes)
+	return c.Create("queue", []string{node, host}, attributes)
 }
 
 // GetQueue ...
 func (c *Client) GetQueue(node string, host string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("queue/" + url.PathEscape(node) + "/" + url.PathEscape(host) + "/" + url.PathEscape(name))
+	return c.Get("queue", []string{node, host, name}, &QueryOptions{Actuals: true})
 }
 
 // DeleteQueue ...
 func (c *Client) DeleteQueue(node string, host string, name string) (res *http.Response, err error) {
-	return c.deleteConfiguredObject("queue/" + url.PathEscape(node) + "/" + url.PathEscape(host) + "/" + url.PathEscape(name))
+	return c.Delete("queue", []string{node, host, name})
 }
 
 // UpdateQueue ...
 func (c *Client) UpdateQueue(node string, host string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("queue/"+url.PathEscape(node)+"/"+url.PathEscape(host)+"/"+url.PathEscape(name), attributes)
+	return c.Update("queue", []string{node, host, name}, attributes)
 }
 
 // CreateExchange...
 func (c *Client) CreateExchange(node string, host string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("exchange/"+url.PathEscape(node)+"/"+url.PathEscape(host), attributes)
+	return c.Create("exchange", []string{node, host}, attributes)
 }
 
 // GetExchange ...
 func (c *Client) GetExchange(node string, host string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("exchange/" + url.PathEscape(node) + "/" + url.PathEscape(host) + "/" + url.PathEscape(name))
+	return c.Get("exchange", []string{node, host, name}, &QueryOptions{Actuals: true})
 }
 
 // DeleteExchange ...
 func (c *Client) DeleteExchange(node string, host string, name string) (res *http.Response, err error) {
-	return c.deleteConfiguredObject("exchange/" + url.PathEscape(node) + "/" + url.PathEscape(host) + "/" + url.PathEscape(name))
+	return c.Delete("exchange", []string{node, host, name})
 }
 
 // UpdateExchange ...
 func (c *Client) UpdateExchange(node string, host string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("exchange/"+url.PathEscape(node)+"/"+url.PathEscape(host)+"/"+url.PathEscape(name), attributes)
+	return c.Update("exchange", []string{node, host, name}, attributes)
 }
 
 func (c *Client) CreateBinding(b *Binding) (*http.Response, error) {
@@ -158,332 +173,394 @@ func (c *Client) GetBinding(b *Binding) (*Binding, error) {
 
 	for _, bnd := range *bindings {
 		if bnd["name"] == b.BindingKey && bnd["destination"] == b.Destination {
-			args := bnd["arguments"]
-			var arguments map[string]string
-			if args != nil {
-				i := args.(map[string]interface{})
-				arguments = *convertToMapOfStrings(&i)
-			}
-			return &Binding{
-				b.BindingKey,
-				b.Destination,
-				b.Exchange,
-				arguments,
-				b.VirtualHostNode,
-				b.VirtualHost}, nil
+			return toBinding(b.VirtualHostNode, b.VirtualHost, b.Exchange, bnd), nil
 		}
 	}
 
 	return nil, nil
 }
 
+// GetExchangeBindings returns all bindings of the exchange
+func (c *Client) GetExchangeBindings(nodeName string, hostName string, exchange string) ([]*Binding, error) {
+	bindings, err := c.fetchExchangeBindings(nodeName, hostName, exchange)
+	if err != nil {
+		return nil, err
+	}
+	return toBindings(nodeName, hostName, exchange, bindings), nil
+}
+
+// GetDestinationBindings returns the bindings of all exchanges of the virtual host to given destination
+func (c *Client) GetDestinationBindings(nodeName string, hostName string, destination string) ([]*Binding, error) {
+	exchanges, err := c.List("exchange", []string{nodeName, hostName}, &QueryOptions{Actuals: false})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Binding, 0)
+	for _, exchange := range *exchanges {
+		name := fmt.Sprintf("%v", exchange["name"])
+		result = append(result, bindingsTo(toBindings(nodeName, hostName, name, bindingsOf(&exchange)), destination)...)
+	}
+	return result, nil
+}
+
+// bindingsTo returns the bindings to given destination
+func bindingsTo(bindings []*Binding, destination string) []*Binding {
+	result := make([]*Binding, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.Destination == destination {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
+
+// toBindings converts the bindings of given exchange
+func toBindings(nodeName string, hostName string, exchange string, bindings *[]map[string]interface{}) []*Binding {
+	result := make([]*Binding, 0, len(*bindings))
+	for _, bnd := range *bindings {
+		result = append(result, toBinding(nodeName, hostName, exchange, bnd))
+	}
+	return result
+}
+
+func toBinding(nodeName string, hostName string, exchange string, bnd map[string]interface{}) *Binding {
+	var arguments map[string]string
+	if args, ok := bnd["arguments"].(map[string]interface{}); ok {
+		arguments = *convertToMapOfStrings(&args)
+	}
+	return &Binding{
+		fmt.Sprintf("%v", bnd["name"]),
+		fmt.Sprintf("%v", bnd["destination"]),
+		exchange,
+		arguments,
+		nodeName,
+		hostName}
+}
+
 func (c *Client) makeBinding(b *Binding, replaceExistingArguments bool) (*http.Response, error) {
 	var arguments = &map[string]interface{}{
 		"destination":              b.Destination,
 		"bindingKey":               b.BindingKey,
 		"arguments":                b.Arguments,
 		"replaceExistingArguments": replaceExistingArguments}
-	return c.restClient.Post("exchange/"+url.PathEscape(b.VirtualHostNode)+"/"+url.PathEscape(b.VirtualHost)+"/"+url.PathEscape(b.Exchange)+"/bind", arguments)
+	return c.postToExchange(b, "bind", arguments)
 }
 
 func (c *Client) DeleteBinding(b *Binding) (*http.Response, error) {
 	var arguments = &map[string]interface{}{
 		"destination": b.Destination,
 		"bindingKey":  b.BindingKey}
-	return c.restClient.Post("exchange/"+url.PathEscape(b.VirtualHostNode)+"/"+url.PathEscape(b.VirtualHost)+"/"+url.PathEscape(b.Exchange)+"/unbind", arguments)
+	return c.postToExchange(b, "unbind", arguments)
 }
 
-func (c *Client) listConfiguredObjets(path string, actuals bool) (*[]map[string]interface{}, error) {
-	v := url.Values{}
-	v.Set("actuals", strconv.FormatBool(actuals))
-	return c.restClient.GetAsArray(path, v)
+func (c *Client) postToExchange(b *Binding, operation string, arguments *map[string]interface{}) (*http.Response, error) {
+	path := []string{b.VirtualHostNode, b.VirtualHost, b.Exchange}
+	defer c.invalidateCache("exchange", path)
+	return c.sendChange("exchange", path, func(endpoint int) (*http.Response, error) {
+		return c.restClient.PostToEndpoint(endpoint, c.objectPath("exchange", path)+"/"+operation, arguments)
+	})
 }
 
 func (c *Client) GetVirtualHostNodes() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("virtualhostnode", true)
+	return c.List("virtualhostnode", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) GetVirtualHosts() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("virtualhost", true)
+	return c.List("virtualhost", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) GetNodeVirtualHosts(nodeName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("virtualhost/"+url.PathEscape(nodeName), true)
+	return c.List("virtualhost", []string{nodeName}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) getQueues() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("queue", true)
+	return c.List("queue", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) getVirtualHostQueues(nodeName string, hostName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("queue/"+url.PathEscape(nodeName)+"/"+url.PathEscape(hostName), true)
+	return c.List("queue", []string{nodeName, hostName}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) getVirtualHostExchanges(nodeName string, hostName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("exchange/"+url.PathEscape(nodeName)+"/"+url.PathEscape(hostName), true)
+	return c.List("exchange", []string{nodeName, hostName}, &QueryOptions{Actuals: true})
 }
 
+// getExchangeBindings returns the bindings of the exchange, no bindings are returned for non-existing exchange
 func (c *Client) getExchangeBindings(nodeName string, hostName string, exchange string) (*[]map[string]interface{}, error) {
-	path := "exchange/" + url.PathEscape(nodeName) + "/" + url.PathEscape(hostName) + "/" + url.PathEscape(exchange)
-	attributes, err := c.getConfiguredObjectAttributes(path, false)
-	if err != nil {
-		return &[]map[string]interface{}{}, err
-	}
-
-	if len(*attributes) == 0 {
+	bindings, err := c.fetchExchangeBindings(nodeName, hostName, exchange)
+	if IsNotFound(err) {
 		return &[]map[string]interface{}{}, nil
 	}
+	return bindings, err
+}
 
-	bindings := (*attributes)["bindings"]
+// fetchExchangeBindings returns the bindings of the exchange read with effective values,
+// as the broker does not return the bindings with actual values
+func (c *Client) fetchExchangeBindings(nodeName string, hostName string, exchange string) (*[]map[string]interface{}, error) {
+	attributes, err := c.Get("exchange", []string{nodeName, hostName, exchange}, &QueryOptions{Actuals: false})
+	if err != nil {
+		return &[]map[string]interface{}{}, err
+	}
+	return bindingsOf(attributes), nil
+}
 
-	if bindings != nil {
-		bs := bindings.([]interface{})
-		result := make([]map[string]interface{}, len(bs))
-		for idx, bn := range bs {
-			bnd := bn.(map[string]interface{})
-			result[idx] = bnd
+// bindingsOf returns the bindings from effective attributes of the exchange
+func bindingsOf(exchange *map[string]interface{}) *[]map[string]interface{} {
+	items, _ := (*exchange)["bindings"].([]interface{})
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if bnd, ok := item.(map[string]interface{}); ok {
+			result = append(result, bnd)
 		}
-		return &result, nil
 	}
-	return &[]map[string]interface{}{}, nil
+	return &result
 }
 
 func (c *Client) CreateAuthenticationProvider(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("authenticationprovider", attributes)
+	return c.Create("authenticationprovider", nil, attributes)
 }
 
 func (c *Client) GetAuthenticationProvider(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("authenticationprovider/" + url.PathEscape(name))
+	return c.Get("authenticationprovider", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteAuthenticationProvider(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("authenticationprovider/" + url.PathEscape(name))
+	return c.Delete("authenticationprovider", []string{name})
 }
 
 func (c *Client) UpdateAuthenticationProvider(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("authenticationprovider/"+url.PathEscape(name), attributes)
+	return c.Update("authenticationprovider", []string{name}, attributes)
 }
 
 func (c *Client) GetAuthenticationProviders() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("authenticationprovider", true)
+	return c.List("authenticationprovider", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateUser(authenticationProvider string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("user/"+url.PathEscape(authenticationProvider), attributes)
+	return c.Create("user", []string{authenticationProvider}, attributes)
 }
 
 func (c *Client) GetUser(authenticationProvider string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("user/" + url.PathEscape(authenticationProvider) + "/" + url.PathEscape(name))
+	return c.Get("user", []string{authenticationProvider, name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteUser(authenticationProvider string, name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("user/" + url.PathEscape(authenticationProvider) + "/" + url.PathEscape(name))
+	return c.Delete("user", []string{authenticationProvider, name})
 }
 
 func (c *Client) UpdateUser(authenticationProvider string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("user/"+url.PathEscape(authenticationProvider)+"/"+url.PathEscape(name), attributes)
+	return c.Update("user", []string{authenticationProvider, name}, attributes)
 }
 
 func (c *Client) GetUsers(authenticationProvider string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("user/"+url.PathEscape(authenticationProvider), true)
+	return c.List("user", []string{authenticationProvider}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateGroupProvider(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("groupprovider", attributes)
+	return c.Create("groupprovider", nil, attributes)
 }
 
 func (c *Client) GetGroupProvider(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("groupprovider/" + url.PathEscape(name))
+	return c.Get("groupprovider", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteGroupProvider(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("groupprovider/" + url.PathEscape(name))
+	return c.Delete("groupprovider", []string{name})
 }
 
 func (c *Client) UpdateGroupProvider(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("groupprovider/"+url.PathEscape(name), attributes)
+	return c.Update("groupprovider", []string{name}, attributes)
 }
 
 func (c *Client) GetGroupProviders() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("groupprovider", true)
+	return c.List("groupprovider", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateGroup(groupProvider string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("group/"+url.PathEscape(groupProvider), attributes)
+	return c.Create("group", []string{groupProvider}, attributes)
 }
 
 func (c *Client) GetGroup(groupProvider string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("group/" + url.PathEscape(groupProvider) + "/" + url.PathEscape(name))
+	return c.Get("group", []string{groupProvider, name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteGroup(groupProvider string, name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("group/" + url.PathEscape(groupProvider) + "/" + url.PathEscape(name))
+	return c.Delete("group", []string{groupProvider, name})
 }
 
 func (c *Client) UpdateGroup(groupProvider string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("group/"+url.PathEscape(groupProvider)+"/"+url.PathEscape(name), attributes)
+	return c.Update("group", []string{groupProvider, name}, attributes)
 }
 
 func (c *Client) GetGroups(groupProvider string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("group/"+url.PathEscape(groupProvider), true)
+	return c.List("group", []string{groupProvider}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateGroupMember(groupProvider string, groupName string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("groupmember/"+url.PathEscape(groupProvider)+"/"+url.PathEscape(groupName), attributes)
+	return c.Create("groupmember", []string{groupProvider, groupName}, attributes)
 }
 
 func (c *Client) GetGroupMember(groupProvider string, groupName string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("groupmember/" + url.PathEscape(groupProvider) + "/" + url.PathEscape(groupName) + "/" + url.PathEscape(name))
+	return c.Get("groupmember", []string{groupProvider, groupName, name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteGroupMember(groupProvider string, groupName string, name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("groupmember/" + url.PathEscape(groupProvider) + "/" + url.PathEscape(groupName) + "/" + url.PathEscape(name))
+	return c.Delete("groupmember", []string{groupProvider, groupName, name})
 }
 
 func (c *Client) UpdateGroupMember(groupProvider string, groupName string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("groupmember/"+url.PathEscape(groupProvider)+"/"+url.PathEscape(groupName)+"/"+url.PathEscape(name), attributes)
+	return c.Update("groupmember", []string{groupProvider, groupName, name}, attributes)
 }
 
 func (c *Client) GetGroupMembers(groupProvider string, groupName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("groupmember/"+url.PathEscape(groupProvider)+"/"+url.PathEscape(groupName), true)
+	return c.List("groupmember", []string{groupProvider, groupName}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateAccessControlProvider(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("accesscontrolprovider", attributes)
+	return c.Create("accesscontrolprovider", nil, attributes)
 }
 
 func (c *Client) GetAccessControlProvider(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("accesscontrolprovider/" + url.PathEscape(name))
+	return c.Get("accesscontrolprovider", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteAccessControlProvider(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("accesscontrolprovider/" + url.PathEscape(name))
+	return c.Delete("accesscontrolprovider", []string{name})
 }
 
 func (c *Client) UpdateAccessControlProvider(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("accesscontrolprovider/"+url.PathEscape(name), attributes)
+	return c.Update("accesscontrolprovider", []string{name}, attributes)
 }
 
 func (c *Client) GetAccessControlProviders() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("accesscontrolprovider", true)
+	return c.List("accesscontrolprovider", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateKeyStore(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("keystore", attributes)
+	return c.Create("keystore", nil, attributes)
 }
 
 func (c *Client) GetKeyStore(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("keystore/" + url.PathEscape(name))
+	return c.Get("keystore", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteKeyStore(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("keystore/" + url.PathEscape(name))
+	return c.Delete("keystore", []string{name})
 }
 
 func (c *Client) UpdateKeyStore(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("keystore/"+url.PathEscape(name), attributes)
+	return c.Update("keystore", []string{name}, attributes)
 }
 
 func (c *Client) GetKeyStores() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("keystore", true)
+	return c.List("keystore", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateTrustStore(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("truststore", attributes)
+	return c.Create("truststore", nil, attributes)
 }
 
 func (c *Client) GetTrustStore(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("truststore/" + url.PathEscape(name))
+	return c.Get("truststore", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteTrustStore(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("truststore/" + url.PathEscape(name))
+	return c.Delete("truststore", []string{name})
 }
 
 func (c *Client) UpdateTrustStore(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("truststore/"+url.PathEscape(name), attributes)
+	return c.Update("truststore", []string{name}, attributes)
 }
 
 func (c *Client) GetTrustStores() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("truststore", true)
+	return c.List("truststore", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreatePort(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("port", attributes)
+	return c.Create("port", nil, attributes)
 }
 
 func (c *Client) GetPort(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("port/" + url.PathEscape(name))
+	return c.Get("port", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeletePort(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("port/" + url.PathEscape(name))
+	return c.Delete("port", []string{name})
 }
 
 func (c *Client) UpdatePort(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("port/"+url.PathEscape(name), attributes)
+	return c.Update("port", []string{name}, attributes)
 }
 
 func (c *Client) GetPorts() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("port", true)
+	return c.List("port", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateVirtualHostAlias(portName string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("virtualhostalias/"+url.PathEscape(portName), attributes)
+	return c.Create("virtualhostalias", []string{portName}, attributes)
 }
 
 func (c *Client) GetVirtualHostAlias(portName string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("virtualhostalias/" + url.PathEscape(portName) + "/" + url.PathEscape(name))
+	return c.Get("virtualhostalias", []string{portName, name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteVirtualHostAlias(portName string, name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("virtualhostalias/" + url.PathEscape(portName) + "/" + url.PathEscape(name))
+	return c.Delete("virtualhostalias", []string{portName, name})
 }
 
 func (c *Client) UpdateVirtualHostAlias(portName string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("virtualhostalias/"+url.PathEscape(portName)+"/"+url.PathEscape(name), attributes)
+	return c.Update("virtualhostalias", []string{portName, name}, attributes)
 }
 
 func (c *Client) GetVirtualHostAliases(portName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("virtualhostalias/"+url.PathEscape(portName), true)
+	return c.List("virtualhostalias", []string{portName}, &QueryOptions{Actuals: true})
+}
+
+// GetBroker returns effective attributes of the broker without its children
+func (c *Client) GetBroker() (*map[string]interface{}, error) {
+	v := url.Values{}
+	v.Set("actuals", "false")
+	v.Set("depth", "0")
+	return c.restClient.GetAsMap(c.modelPath("broker"), v)
 }
 
 func (c *Client) CreateBrokerLogger(attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("brokerlogger", attributes)
+	return c.Create("brokerlogger", nil, attributes)
 }
 
 func (c *Client) GetBrokerLogger(name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("brokerlogger/" + url.PathEscape(name))
+	return c.Get("brokerlogger", []string{name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteBrokerLogger(name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("brokerlogger/" + url.PathEscape(name))
+	return c.Delete("brokerlogger", []string{name})
 }
 
 func (c *Client) UpdateBrokerLogger(name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("brokerlogger/"+url.PathEscape(name), attributes)
+	return c.Update("brokerlogger", []string{name}, attributes)
 }
 
 func (c *Client) GetBrokerLoggers() (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("brokerlogger", true)
+	return c.List("brokerlogger", nil, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) CreateBrokerLoggerRule(loggerName string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("brokerloginclusionrule/"+url.PathEscape(loggerName), attributes)
+	return c.Create("brokerloginclusionrule", []string{loggerName}, attributes)
 }
 
 func (c *Client) GetBrokerLoggerRule(loggerName string, name string) (*map[string]interface{}, error) {
-	return c.getConfiguredObject("brokerloginclusionrule/" + url.PathEscape(loggerName) + "/" + url.PathEscape(name))
+	return c.Get("brokerloginclusionrule", []string{loggerName, name}, &QueryOptions{Actuals: true})
 }
 
 func (c *Client) DeleteBrokerLoggerRule(loggerName string, name string) (*http.Response, error) {
-	return c.deleteConfiguredObject("brokerloginclusionrule/" + url.PathEscape(loggerName) + "/" + url.PathEscape(name))
+	return c.Delete("brokerloginclusionrule", []string{loggerName, name})
 }
 
 func (c *Client) UpdateBrokerLoggerRule(loggerName string, name string, attributes *map[string]interface{}) (*http.Response, error) {
-	return c.restClient.Post("brokerloginclusionrule/"+url.PathEscape(loggerName)+"/"+url.PathEscape(name), attributes)
+	return c.Update("brokerloginclusionrule", []string{loggerName, name}, attributes)
 }
 
 func (c *Client) GetBrokerLoggerRules(loggerName string) (*[]map[string]interface{}, error) {
-	return c.listConfiguredObjets("brokerloginclusionrule/"+url.PathEscape(loggerName), true)
+	return c.List("brokerloginclusionrule", []string{loggerName}, &QueryOptions{Actuals: true})
 }
@@ -0,0 +1,373 @@
+package qpid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func newFakeBrokerClient(t *testing.T) (*Client, *fakeBroker) {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	client, err := NewClient(broker.URL(), fakeBrokerUsername, fakeBrokerPassword, "", http.DefaultTransport)
+	if err != nil {
+		broker.Close()
+		t.Fatalf("unable to create client: %v", err)
+	}
+	err = client.ResolveModelVersion()
+	if err != nil {
+		broker.Close()
+		t.Fatalf("unable to resolve model version: %v", err)
+	}
+	return client, broker
+}
+
+func createFakeVirtualHost(t *testing.T, client *Client, node string, host string) {
+	resp, err := client.CreateVirtualHostNode(&map[string]interface{}{"name": node, "type": "Memory"})
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unable to create virtual host node: %v", err)
+	}
+	resp, err = client.CreateVirtualHost(node, &map[string]interface{}{"name": host, "type": "Memory"})
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unable to create virtual host: %v", err)
+	}
+}
+
+func TestClientConfiguredObjects(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	if client.ModelVersion() != "v7.1" {
+		t.Fatalf("unexpected model version '%s'", client.ModelVersion())
+	}
+
+	resp, err := client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue"})
+	if err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("queue is created on non-existing virtual host: %v", err)
+	}
+
+	createFakeVirtualHost(t, client, "node", "host")
+	resp, err = client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue", "maximumDeliveryAttempts": 5})
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+
+	queue, err := client.Get("queue", []string{"node", "host", "queue"}, &QueryOptions{Actuals: true})
+	if err != nil {
+		t.Fatalf("unable to get queue: %v", err)
+	}
+	id, err := objectID(queue)
+	if err != nil || id == "" {
+		t.Fatalf("queue id is not returned: %v", err)
+	}
+	if _, set := (*queue)["type"]; set {
+		t.Errorf("actual attributes contain default type: %v", *queue)
+	}
+
+	queue, err = client.Get("queue", []string{"node", "host", "queue"}, &QueryOptions{Actuals: false})
+	if err != nil || (*queue)["type"] != "standard" || (*queue)["maximumDeliveryAttempts"] != json.Number("5") {
+		t.Fatalf("unexpected effective attributes %v: %v", queue, err)
+	}
+
+	resp, err = client.UpdateQueue("node", "host", "queue", &map[string]interface{}{"maximumDeliveryAttempts": nil})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unable to update queue: %v", err)
+	}
+	queue, err = client.GetQueue("node", "host", "queue")
+	if err != nil {
+		t.Fatalf("unable to get queue: %v", err)
+	}
+	if _, set := (*queue)["maximumDeliveryAttempts"]; set {
+		t.Errorf("removed attribute is returned: %v", *queue)
+	}
+
+	queues, err := client.List("queue", nil, &QueryOptions{Actuals: true})
+	if err != nil || len(*queues) != 1 {
+		t.Fatalf("unexpected queues %v: %v", queues, err)
+	}
+
+	_, err = client.DeleteQueue("node", "host", "queue")
+	if err != nil {
+		t.Fatalf("unable to delete queue: %v", err)
+	}
+	_, err = client.GetQueue("node", "host", "queue")
+	if !IsNotFound(err) {
+		t.Fatalf("deleted queue is found: %v", err)
+	}
+
+	queues, err = client.getVirtualHostQueues("node", "other")
+	if err != nil || len(*queues) != 0 {
+		t.Fatalf("unexpected queues of non-existing virtual host %v: %v", queues, err)
+	}
+}
+
+func TestClientModelVersionProbeWithConcurrentRequests(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	createFakeVirtualHost(t, client, "node", "host")
+	var wg sync.WaitGroup
+	failures := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			// the model version is not supported by the broker
+			if _, err := client.getBrokerModelVersion("v99.0"); !IsNotFound(err) {
+				failures <- fmt.Errorf("unexpected result of probing unsupported model version: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetVirtualHost("node", "host"); err != nil {
+				failures <- fmt.Errorf("unable to get virtual host while probing model version: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(failures)
+	for err := range failures {
+		t.Error(err)
+	}
+}
+
+// requestPathsTransport records paths of the sent requests
+type requestPathsTransport struct {
+	mutex sync.Mutex
+	paths []string
+}
+
+func (t *requestPathsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	t.paths = append(t.paths, req.URL.Path)
+	t.mutex.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientResolveModelVersion(t *testing.T) {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	defer broker.Close()
+
+	versions := []struct {
+		configured string
+		resolved   string
+		probes     []string
+		failing    bool
+	}{
+		{configured: "", resolved: "v7.1", probes: []string{"/api/latest/broker"}},
+		{configured: "v7.0", resolved: "v7.0", probes: []string{"/api/v7.0/broker"}},
+		{configured: "v9.0", resolved: "v9.0", probes: []string{"/api/v9.0/broker"}, failing: true},
+	}
+	for _, v := range versions {
+		transport := &requestPathsTransport{}
+		client, err := NewClient(broker.URL(), fakeBrokerUsername, fakeBrokerPassword, v.configured, transport)
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+		err = client.ResolveModelVersion()
+		if (err != nil) != v.failing || client.ModelVersion() != v.resolved {
+			t.Errorf("unexpected model version '%s' resolved for '%s': %v", client.ModelVersion(), v.configured, err)
+		}
+		if fmt.Sprint(transport.paths) != fmt.Sprint(v.probes) {
+			t.Errorf("unexpected probes %v resolving model version '%s', expected %v", transport.paths, v.configured, v.probes)
+		}
+	}
+}
+
+func TestClientBindings(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	createFakeVirtualHost(t, client, "node", "host")
+	_, err := client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue"})
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+
+	binding := &Binding{"key", "queue", "amq.direct", map[string]string{"x-filter-jms-selector": "a=1"}, "node", "host"}
+	_, err = client.CreateBinding(binding)
+	if err != nil {
+		t.Fatalf("unable to create binding: %v", err)
+	}
+
+	found, err := client.GetBinding(binding)
+	if err != nil || found == nil || found.Arguments["x-filter-jms-selector"] != "a=1" {
+		t.Fatalf("unexpected binding %v: %v", found, err)
+	}
+
+	bindings, err := client.GetExchangeBindings("node", "host", "amq.direct")
+	if err != nil || len(bindings) != 1 || bindings[0].BindingKey != "key" || bindings[0].Exchange != "amq.direct" {
+		t.Fatalf("unexpected exchange bindings %v: %v", bindings, err)
+	}
+	bindings, err = client.GetDestinationBindings("node", "host", "queue")
+	if err != nil || len(bindings) != 1 || bindings[0].Destination != "queue" {
+		t.Fatalf("unexpected destination bindings %v: %v", bindings, err)
+	}
+	_, err = client.GetExchangeBindings("node", "host", "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("unexpected result of getting bindings of non-existing exchange: %v", err)
+	}
+
+	_, err = client.DeleteBinding(binding)
+	if err != nil {
+		t.Fatalf("unable to delete binding: %v", err)
+	}
+	found, err = client.GetBinding(binding)
+	if err != nil || found != nil {
+		t.Fatalf("deleted binding is found %v: %v", found, err)
+	}
+}
+
+func TestClientOperations(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	createFakeVirtualHost(t, client, "node", "host")
+	_, err := client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue"})
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+
+	cleared, err := client.ClearQueue("node", "host", "queue")
+	if err != nil || cleared != 0 {
+		t.Fatalf("unexpected result of clearing queue %d: %v", cleared, err)
+	}
+
+	client.SetReadCacheEnabled(true)
+	_, err = client.GetQueue("node", "host", "queue")
+	if err != nil {
+		t.Fatalf("unable to get queue: %v", err)
+	}
+	statistics, err := client.GetVirtualHostStatistics("node", "host")
+	if err != nil || (*statistics)["queueCount"] != json.Number("1") {
+		t.Fatalf("unexpected virtual host statistics %v: %v", statistics, err)
+	}
+	if len(client.cache.hierarchies) != 1 {
+		t.Fatalf("read cache is invalidated by getting statistics")
+	}
+	err = client.ResetVirtualHostStatistics("node", "host")
+	if err != nil || len(client.cache.hierarchies) != 0 {
+		t.Fatalf("read cache is not invalidated by resetting statistics: %v", err)
+	}
+	client.SetReadCacheEnabled(false)
+
+	_, err = client.MoveMessages("node", "host", "queue", "missing", nil)
+	if err == nil {
+		t.Fatalf("messages are moved to non-existing queue")
+	}
+
+	_, err = client.InvokeOperation("queue", []string{"node", "host", "queue"}, "unknown", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("unexpected result of unknown operation: %v", err)
+	}
+}
+
+func TestClientSessionReuse(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	createFakeVirtualHost(t, client, "node", "host")
+	for i := 0; i < 3; i++ {
+		_, err := client.GetVirtualHost("node", "host")
+		if err != nil {
+			t.Fatalf("unable to get virtual host: %v", err)
+		}
+	}
+	if broker.Authentications() != 1 {
+		t.Fatalf("unexpected number of authentications %d, expected session to be reused", broker.Authentications())
+	}
+
+	broker.ExpireSessions()
+	_, err := client.GetVirtualHost("node", "host")
+	if err != nil {
+		t.Fatalf("unable to get virtual host after session expiry: %v", err)
+	}
+	_, err = client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue"})
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+	if broker.Authentications() != 2 {
+		t.Fatalf("unexpected number of authentications %d, expected re-authentication after session expiry", broker.Authentications())
+	}
+
+	broker.ExpireSessions()
+	_, err = client.CreateQueue("node", "host", &map[string]interface{}{"name": "other"})
+	if err != nil {
+		t.Fatalf("unable to create queue after session expiry: %v", err)
+	}
+	queue, err := client.GetQueue("node", "host", "other")
+	if err != nil || (*queue)["name"] != "other" {
+		t.Fatalf("unexpected queue %v: %v", queue, err)
+	}
+
+	// requests rejected concurrently after the session expiry are all resent
+	broker.ExpireSessions()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetVirtualHost("node", "host"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unable to get virtual host after concurrent session expiry: %v", err)
+	}
+}
+
+// uncompressedResponsesTransport counts responses decompressed by the transport
+type uncompressedResponsesTransport struct {
+	transport    http.RoundTripper
+	responses    int
+	uncompressed int
+}
+
+func (t *uncompressedResponsesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil {
+		t.responses++
+		if resp.Uncompressed {
+			t.uncompressed++
+		}
+	}
+	return resp, err
+}
+
+func TestClientCompressedResponsesAndLargeNumbers(t *testing.T) {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	defer broker.Close()
+
+	transport := &uncompressedResponsesTransport{transport: &http.Transport{}}
+	client, err := NewClient(broker.URL(), fakeBrokerUsername, fakeBrokerPassword, "v7.1", transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	createFakeVirtualHost(t, client, "node", "host")
+	_, err = client.CreateQueue("node", "host", &map[string]interface{}{"name": "queue", "maximumQueueDepthBytes": int64(9007199254740993)})
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+
+	queue, err := client.GetQueue("node", "host", "queue")
+	if err != nil {
+		t.Fatalf("unable to get queue: %v", err)
+	}
+	if (*queue)["maximumQueueDepthBytes"] != json.Number("9007199254740993") {
+		t.Fatalf("precision of large number is lost: %v", (*queue)["maximumQueueDepthBytes"])
+	}
+	if transport.uncompressed == 0 || transport.uncompressed != transport.responses {
+		t.Fatalf("unexpected number of compressed responses %d of %d", transport.uncompressed, transport.responses)
+	}
+
+	queues, err := client.getVirtualHostQueues("node", "host")
+	if err != nil || len(*queues) != 1 || (*queues)[0]["maximumQueueDepthBytes"] != json.Number("9007199254740993") {
+		t.Fatalf("unexpected queues %v: %v", queues, err)
+	}
+}
@@ -0,0 +1,78 @@
+package qpid
+
+import (
+	"github.com/terraform-providers/terraform-provider-qpid/qpid/api"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// QueryOptions are parameters of requests getting configured objects
+type QueryOptions = api.QueryOptions
+
+// ConfiguredObjectClient provides operations on broker configured objects of any category
+type ConfiguredObjectClient = api.ConfiguredObjectClient
+
+var _ ConfiguredObjectClient = &Client{}
+
+// virtualHostCategories are categories of objects managed on the master of virtual host node
+var virtualHostCategories = map[string]struct{}{
+	"virtualhost":                      {},
+	"queue":                            {},
+	"exchange":                         {},
+	"virtualhostlogger":                {},
+	"virtualhostloginclusionrule":      {},
+	"virtualhostaccesscontrolprovider": {},
+}
+
+// Create creates an object of given category with given parents
+func (c *Client) Create(category string, parents []string, attributes *map[string]interface{}) (*http.Response, error) {
+	defer c.invalidateCache(category, parents)
+	return c.sendChange(category, parents, func(endpoint int) (*http.Response, error) {
+		return c.restClient.PostToEndpoint(endpoint, c.objectPath(category, parents), attributes)
+	})
+}
+
+// Get returns attributes of the object of given category with given path
+func (c *Client) Get(category string, path []string, options *QueryOptions) (*map[string]interface{}, error) {
+	if attributes, cached, err := c.getCached(category, path, options); cached {
+		return attributes, err
+	}
+	return c.restClient.GetAsMap(c.objectPath(category, path), options.Values())
+}
+
+// Update changes given attributes of the object of given category with given path
+func (c *Client) Update(category string, path []string, attributes *map[string]interface{}) (*http.Response, error) {
+	defer c.invalidateCache(category, path)
+	return c.sendChange(category, path, func(endpoint int) (*http.Response, error) {
+		return c.restClient.UpdateOnEndpoint(endpoint, c.objectPath(category, path), attributes)
+	})
+}
+
+// Delete deletes the object of given category with given path
+func (c *Client) Delete(category string, path []string) (*http.Response, error) {
+	defer c.invalidateCache(category, path)
+	return c.sendChange(category, path, func(endpoint int) (*http.Response, error) {
+		return c.restClient.DeleteFromEndpoint(endpoint, c.objectPath(category, path))
+	})
+}
+
+// List returns attributes of all objects of given category with given parents.
+// Objects of all parents are returned when only some of the ancestors are specified.
+func (c *Client) List(category string, parents []string, options *QueryOptions) (*[]map[string]interface{}, error) {
+	objects, err := c.restClient.GetAsArray(c.objectPath(category, parents), options.Values())
+	if IsNotFound(err) {
+		// parent object does not exist, thus, there are no children
+		return &[]map[string]interface{}{}, nil
+	}
+	return objects, err
+}
+
+func configuredObjectPath(category string, path []string) string {
+	parts := make([]string, len(path)+1)
+	parts[0] = category
+	for i, name := range path {
+		parts[i+1] = url.PathEscape(name)
+	}
+	return strings.Join(parts, "/")
+}
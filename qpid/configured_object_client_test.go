@@ -0,0 +1,91 @@
+package qpid
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestQueryOptionsValues(t *testing.T) {
+	options := map[string]*QueryOptions{
+		"":              nil,
+		"actuals=false": {},
+		"actuals=true":  {Actuals: true},
+		"actuals=true&depth=2&excludeInheritedContext=true&oversize=100": {Actuals: true, Depth: 2, Oversize: 100, ExcludeInheritedContext: true},
+	}
+	for expected, o := range options {
+		if query := o.Values().Encode(); query != expected {
+			t.Errorf("unexpected query '%s' of %+v, expected '%s'", query, o, expected)
+		}
+	}
+}
+
+func TestConfiguredObjectPath(t *testing.T) {
+	paths := []struct {
+		category string
+		path     []string
+		expected string
+	}{
+		{category: "virtualhostnode", expected: "virtualhostnode"},
+		{category: "queue", path: []string{"node", "host"}, expected: "queue/node/host"},
+		{category: "queue", path: []string{"node", "host", "a/b"}, expected: "queue/node/host/a%2Fb"},
+		{category: "exchange", path: []string{"node", "host", "with space"}, expected: "exchange/node/host/with%20space"},
+	}
+	for _, p := range paths {
+		if result := configuredObjectPath(p.category, p.path); result != p.expected {
+			t.Errorf("unexpected path '%s', expected '%s'", result, p.expected)
+		}
+	}
+}
+
+func TestClientConfiguredObjectsByPath(t *testing.T) {
+	client, broker := newFakeBrokerClient(t)
+	defer broker.Close()
+
+	createFakeVirtualHost(t, client, "node", "host")
+	createFakeVirtualHost(t, client, "other", "host")
+	for _, node := range []string{"node", "other"} {
+		resp, err := client.Create("queue", []string{node, "host"}, &map[string]interface{}{"name": "a/b c"})
+		if err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("unable to create queue with escaped name: %v", err)
+		}
+	}
+
+	_, err := client.Update("queue", []string{"node", "host", "a/b c"}, &map[string]interface{}{"maximumDeliveryAttempts": 5})
+	if err != nil {
+		t.Fatalf("unable to update queue: %v", err)
+	}
+	queue, err := client.Get("queue", []string{"node", "host", "a/b c"}, nil)
+	if err != nil || (*queue)["name"] != "a/b c" || (*queue)["maximumDeliveryAttempts"] != json.Number("5") {
+		t.Fatalf("unexpected queue %v: %v", queue, err)
+	}
+
+	lists := []struct {
+		parents []string
+		count   int
+	}{
+		{parents: []string{"node", "host"}, count: 1},
+		{parents: []string{"node"}, count: 1},
+		{parents: nil, count: 2},
+		{parents: []string{"node", "missing"}, count: 0},
+	}
+	for _, l := range lists {
+		queues, err := client.List("queue", l.parents, &QueryOptions{Actuals: true})
+		if err != nil || len(*queues) != l.count {
+			t.Errorf("unexpected queues of %v %v: %v", l.parents, queues, err)
+		}
+	}
+
+	_, err = client.Delete("queue", []string{"node", "host", "a/b c"})
+	if err != nil {
+		t.Fatalf("unable to delete queue: %v", err)
+	}
+	_, err = client.Get("queue", []string{"node", "host", "a/b c"}, nil)
+	if !IsNotFound(err) {
+		t.Errorf("unexpected error getting deleted queue: %v", err)
+	}
+	queues, err := client.List("queue", nil, nil)
+	if err != nil || len(*queues) != 1 {
+		t.Errorf("unexpected queues after deletion %v: %v", queues, err)
+	}
+}
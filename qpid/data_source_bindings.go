@@ -0,0 +1,116 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"sort"
+)
+
+func dataSourceBindings() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBindingsRead,
+
+		Schema: map[string]*schema.Schema{
+			"virtual_host_node": {
+				Type:        schema.TypeString,
+				Description: "The name of Virtual Host Node",
+				Required:    true,
+			},
+			"virtual_host": {
+				Type:        schema.TypeString,
+				Description: "The name of Virtual Host",
+				Required:    true,
+			},
+			"exchange": {
+				Type:        schema.TypeString,
+				Description: "The name of exchange to list bindings of",
+				Optional:    true,
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Description: "The name of destination to list bindings to, together with exchange, the bindings of the exchange to the destination are listed",
+				Optional:    true,
+			},
+			"bindings": {
+				Type:        schema.TypeList,
+				Description: "Bindings sorted by exchange, destination and binding key",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"exchange": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"destination": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"binding_key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"arguments": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBindingsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+	exchange := d.Get("exchange").(string)
+	destination := d.Get("destination").(string)
+
+	var bindings []*Binding
+	var err error
+	switch {
+	case exchange != "":
+		bindings, err = client.GetExchangeBindings(node, host, exchange)
+		if IsNotFound(err) {
+			return fmt.Errorf("exchange '%s' is not found on virtual host '%s/%s'", exchange, node, host)
+		}
+		if destination != "" {
+			bindings = bindingsTo(bindings, destination)
+		}
+	case destination != "":
+		bindings, err = client.GetDestinationBindings(node, host, destination)
+	default:
+		return fmt.Errorf("either exchange or destination needs to be set")
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].Exchange != bindings[j].Exchange {
+			return bindings[i].Exchange < bindings[j].Exchange
+		}
+		if bindings[i].Destination != bindings[j].Destination {
+			return bindings[i].Destination < bindings[j].Destination
+		}
+		return bindings[i].BindingKey < bindings[j].BindingKey
+	})
+
+	items := make([]interface{}, 0, len(bindings))
+	for _, binding := range bindings {
+		items = append(items, map[string]interface{}{
+			"exchange":    binding.Exchange,
+			"destination": binding.Destination,
+			"binding_key": binding.BindingKey,
+			"arguments":   binding.Arguments,
+		})
+	}
+
+	d.SetId(node + "|" + host + "|" + exchange + "|" + destination)
+	return d.Set("bindings", items)
+}
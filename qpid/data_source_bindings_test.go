@@ -0,0 +1,65 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceBindings(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceBindingCheckDestroy(),
+		Steps: []resource.TestStep{
+			{
+				// data source of non-existing exchange fails
+				Config: `
+data "qpid_bindings" "missing" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    exchange = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile("exchange 'missing' is not found"),
+			},
+			{
+				// create binding, the data sources are evaluated after creation of binding on the next step
+				Config: testAcceptanceBindingConfigMinimal,
+			},
+			{
+				// list bindings of exchange and bindings to destination
+				Config: testAcceptanceBindingConfigMinimal + `
+data "qpid_bindings" "exchange" {
+    virtual_host_node = ` + testAcceptanceBindingResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceBindingResource + `.virtual_host
+    exchange = ` + testAcceptanceBindingResource + `.exchange
+}
+
+data "qpid_bindings" "destination" {
+    virtual_host_node = ` + testAcceptanceBindingResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceBindingResource + `.virtual_host
+    destination = ` + testAcceptanceBindingResource + `.destination
+}
+
+data "qpid_bindings" "exchange_destination" {
+    virtual_host_node = ` + testAcceptanceBindingResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceBindingResource + `.virtual_host
+    exchange = ` + testAcceptanceBindingResource + `.exchange
+    destination = "missing"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qpid_bindings.exchange", "bindings.#", "1"),
+					resource.TestCheckResourceAttr("data.qpid_bindings.exchange", "bindings.0.destination", testAcceptanceQueueName),
+					resource.TestCheckResourceAttr("data.qpid_bindings.exchange", "bindings.0.binding_key", testBindingKey),
+					resource.TestCheckResourceAttr("data.qpid_bindings.exchange", "bindings.0.arguments.x-filter-jms-selector", "foo='bar'"),
+					resource.TestCheckResourceAttr("data.qpid_bindings.destination", "bindings.#", "1"),
+					resource.TestCheckResourceAttr("data.qpid_bindings.destination", "bindings.0.exchange", testAcceptanceExchangeName),
+					resource.TestCheckResourceAttr("data.qpid_bindings.exchange_destination", "bindings.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,151 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"log"
+)
+
+func dataSourceBroker() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBrokerRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"product_version": {
+				Type:        schema.TypeString,
+				Description: "Broker product version",
+				Computed:    true,
+			},
+			"build_version": {
+				Type:        schema.TypeString,
+				Description: "Broker build version",
+				Computed:    true,
+			},
+			"model_version": {
+				Type:        schema.TypeString,
+				Description: "The newest model version supported by the broker",
+				Computed:    true,
+			},
+			"supported_model_versions": {
+				Type:        schema.TypeList,
+				Description: "Model versions supported by the provider not newer than the broker model version, empty when the broker model version is not reported or unknown",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"operating_system": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Description: "Java platform the broker runs on",
+				Computed:    true,
+			},
+			"process_pid": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"number_of_available_processors": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"default_virtual_host": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"supported_virtual_host_node_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"supported_virtual_host_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"supported_queue_types": {
+				Type:        schema.TypeList,
+				Description: "Supported queue types, only available when the broker provides metadata",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"supported_authentication_provider_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBrokerRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	attributes, err := client.GetBroker()
+	if err != nil {
+		return err
+	}
+
+	err = applyDataSourceAttributes(d, dataSourceBroker().Schema, attributes,
+		"model_version", "supported_model_versions", "supported_virtual_host_node_types", "supported_virtual_host_types",
+		"supported_queue_types", "supported_authentication_provider_types")
+	if err != nil {
+		return err
+	}
+
+	modelVersion := brokerModelVersion(attributes)
+	err = d.Set("model_version", modelVersion)
+	if err != nil {
+		return err
+	}
+	modelVersions, err := commonModelVersions(modelVersion)
+	if err != nil {
+		log.Printf("[WARN] Qpid: supported model versions are unknown for broker model version '%s': %v", modelVersion, err)
+		modelVersions = []string{}
+	}
+	err = d.Set("supported_model_versions", modelVersions)
+	if err != nil {
+		return err
+	}
+
+	types := map[string]interface{}{
+		"supported_virtual_host_node_types":       (*attributes)["supportedVirtualHostNodeTypes"],
+		"supported_virtual_host_types":            (*attributes)["supportedVirtualHostTypes"],
+		"supported_authentication_provider_types": (*attributes)["supportedAuthenticationProviders"],
+		"supported_queue_types":                   nil,
+	}
+	categories := map[string]string{
+		"supported_virtual_host_node_types":       "virtualhostnode",
+		"supported_virtual_host_types":            "virtualhost",
+		"supported_authentication_provider_types": "authenticationprovider",
+		"supported_queue_types":                   "queue",
+	}
+	for key, value := range types {
+		items, isList := value.([]interface{})
+		if isList {
+			value = convertToArrayOfStrings(&items)
+		} else if capabilities := client.Capabilities(); capabilities != nil {
+			value = capabilities.SupportedTypes(categories[key])
+		} else {
+			continue
+		}
+		err = d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceBroker(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcceptancePreCheck(t) },
+		Providers: testAcceptanceProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "qpid_broker" "broker" {
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.qpid_broker.broker", "id"),
+					resource.TestCheckResourceAttrSet("data.qpid_broker.broker", "product_version"),
+					resource.TestMatchResourceAttr("data.qpid_broker.broker", "model_version", regexp.MustCompile(`^v\d+\.\d+$`)),
+					resource.TestCheckResourceAttr("data.qpid_broker.broker", "supported_model_versions.0", supportedModelVersions[0]),
+					resource.TestMatchResourceAttr("data.qpid_broker.broker", "supported_virtual_host_node_types.#", regexp.MustCompile(`^[1-9]\d*$`)),
+					resource.TestMatchResourceAttr("data.qpid_broker.broker", "supported_virtual_host_types.#", regexp.MustCompile(`^[1-9]\d*$`)),
+					resource.TestMatchResourceAttr("data.qpid_broker.broker", "supported_authentication_provider_types.#", regexp.MustCompile(`^[1-9]\d*$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceBrokerUnknownModelVersion(t *testing.T) {
+	brokers := []struct {
+		modelVersion interface{}
+		expected     string
+		versions     int
+	}{
+		{expected: ""},
+		{modelVersion: "1.x", expected: "v1.x"},
+		{modelVersion: "7.1", expected: "v7.1", versions: 4},
+		{modelVersion: "99.0", expected: "v99.0", versions: len(supportedModelVersions)},
+	}
+	for _, b := range brokers {
+		attributes := map[string]interface{}{"id": "broker-id", "name": "broker"}
+		if b.modelVersion != nil {
+			attributes["modelVersion"] = b.modelVersion
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeFakeBrokerResponse(w, http.StatusOK, attributes)
+		}))
+		client, err := NewClient(server.URL, fakeBrokerUsername, fakeBrokerPassword, "v7.1", http.DefaultTransport)
+		if err != nil {
+			server.Close()
+			t.Fatalf("unable to create client: %v", err)
+		}
+
+		d := schema.TestResourceDataRaw(t, dataSourceBroker().Schema, map[string]interface{}{})
+		err = dataSourceBrokerRead(d, client)
+		server.Close()
+		if err != nil {
+			t.Errorf("unable to read broker with model version '%v': %v", b.modelVersion, err)
+			continue
+		}
+		versions := d.Get("supported_model_versions").([]interface{})
+		if d.Get("model_version") != b.expected || len(versions) != b.versions {
+			t.Errorf("unexpected model version '%v' and supported model versions %v of broker with model version '%v'",
+				d.Get("model_version"), versions, b.modelVersion)
+		}
+	}
+}
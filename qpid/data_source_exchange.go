@@ -0,0 +1,30 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceExchange() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceExchangeRead,
+		Schema: dataSourceSchema(resourceExchange().Schema, "name", "virtual_host_node", "virtual_host"),
+	}
+}
+
+func dataSourceExchangeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+	attributes, err := client.Get("exchange", []string{node, host, name}, &QueryOptions{Actuals: false})
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("exchange '%s' is not found on virtual host '%s/%s'", name, node, host)
+		}
+		return err
+	}
+
+	return applyDataSourceAttributes(d, dataSourceExchange().Schema, attributes)
+}
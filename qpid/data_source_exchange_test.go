@@ -0,0 +1,48 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceExchange(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceExchangeCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName, testAcceptanceExchangeName),
+		Steps: []resource.TestStep{
+			{
+				// data source of non-existing object fails
+				Config: `
+data "` + testAcceptanceExchangeResourceName + `" "missing" {
+    name = "missing"
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+}
+`,
+				ExpectError: regexp.MustCompile("exchange 'missing' is not found"),
+			},
+			{
+				// data source exposes attributes of existing object
+				Config: testAcceptanceExchangeConfigMinimal + testAcceptanceDataSourceExchangeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(testAcceptanceDataSourceExchange, "id", testAcceptanceExchangeResource, "id"),
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceExchange, "type", "direct"),
+					// effective values of attributes not set on the exchange are exposed
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceExchange, "unroutable_message_behaviour", "DISCARD"),
+				),
+			},
+		},
+	})
+}
+
+const testAcceptanceDataSourceExchange = "data." + testAcceptanceExchangeResource
+const testAcceptanceDataSourceExchangeConfig = `
+data "` + testAcceptanceExchangeResourceName + `" "` + testAcceptanceExchangeName + `" {
+    name = ` + testAcceptanceExchangeResource + `.name
+    virtual_host_node = ` + testAcceptanceExchangeResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceExchangeResource + `.virtual_host
+}
+`
@@ -0,0 +1,27 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceExchanges() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceExchangesRead,
+		Schema: virtualHostObjectsDataSourceSchema(resourceExchange().Schema, "exchanges"),
+	}
+}
+
+func dataSourceExchangesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+
+	// effective values are filtered, thus, the exchanges can be selected by default values as well
+	exchanges, err := client.List("exchange", []string{node, host}, &QueryOptions{Actuals: false})
+	if err != nil {
+		return err
+	}
+
+	return applyVirtualHostObjectsDataSourceAttributes(d, dataSourceExchanges().Schema, "exchanges", exchanges)
+}
@@ -0,0 +1,45 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"testing"
+)
+
+func TestAcceptanceDataSourceExchanges(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceExchangeCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName, testAcceptanceExchangeName),
+		Steps: []resource.TestStep{
+			{
+				// create exchange, the data sources are evaluated after creation of exchange on the next step
+				Config: testAcceptanceExchangeConfigMinimal,
+			},
+			{
+				// list exchanges filtered by name regex and type
+				Config: testAcceptanceExchangeConfigMinimal + `
+data "qpid_exchanges" "direct" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    type = "direct"
+}
+
+data "qpid_exchanges" "test" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    name_regex = "^test_"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qpid_exchanges.direct", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.qpid_exchanges.direct", "names.0", "amq.direct"),
+					resource.TestCheckResourceAttr("data.qpid_exchanges.direct", "names.1", testAcceptanceExchangeName),
+					resource.TestCheckResourceAttr("data.qpid_exchanges.direct", "exchanges.1.type", "direct"),
+					resource.TestCheckResourceAttr("data.qpid_exchanges.test", "names.#", "1"),
+					resource.TestCheckResourceAttrPair("data.qpid_exchanges.test", "exchanges.0.id", testAcceptanceExchangeResource, "id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,30 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceQueue() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceQueueRead,
+		Schema: dataSourceSchema(resourceQueue().Schema, "name", "virtual_host_node", "virtual_host"),
+	}
+}
+
+func dataSourceQueueRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+	attributes, err := client.Get("queue", []string{node, host, name}, &QueryOptions{Actuals: false})
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("queue '%s' is not found on virtual host '%s/%s'", name, node, host)
+		}
+		return err
+	}
+
+	return applyDataSourceAttributes(d, dataSourceQueue().Schema, attributes)
+}
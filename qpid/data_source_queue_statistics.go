@@ -0,0 +1,95 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// queueStatistics lists the queue statistics exposed as data source attributes with their descriptions
+var queueStatistics = map[string]string{
+	"queue_depth_messages":    "Number of messages on the queue",
+	"queue_depth_bytes":       "Total size of messages on the queue in bytes",
+	"consumer_count":          "Number of consumers of the queue",
+	"oldest_message_age":      "Age of the oldest message on the queue in milliseconds",
+	"total_enqueued_messages": "Total number of messages enqueued since the broker start or statistics reset",
+	"total_enqueued_bytes":    "Total size of messages enqueued since the broker start or statistics reset",
+	"total_dequeued_messages": "Total number of messages dequeued since the broker start or statistics reset",
+	"total_dequeued_bytes":    "Total size of messages dequeued since the broker start or statistics reset",
+	"unacknowledged_messages": "Number of messages delivered to consumers but not acknowledged yet",
+	"available_messages":      "Number of messages available for delivery",
+}
+
+func dataSourceQueueStatistics() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"virtual_host_node": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"virtual_host": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"statistics": {
+			Type:        schema.TypeMap,
+			Description: "All statistics of the queue",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+	for key, description := range queueStatistics {
+		s[key] = &schema.Schema{
+			Type:        schema.TypeInt,
+			Description: description,
+			Computed:    true,
+		}
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceQueueStatisticsRead,
+		Schema: s,
+	}
+}
+
+func dataSourceQueueStatisticsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+	statistics, err := client.GetQueueStatistics(node, host, name)
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("queue '%s' is not found on virtual host '%s/%s'", name, node, host)
+		}
+		return err
+	}
+
+	for key := range queueStatistics {
+		value, ok := (*statistics)[convertToCamelCase(key)]
+		if !ok {
+			continue
+		}
+		number, err := toInt64(value)
+		if err != nil {
+			return fmt.Errorf("unexpected value of queue statistic '%s': %v", key, err)
+		}
+		err = d.Set(key, int(number))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = d.Set("statistics", *convertToMapOfStrings(statistics))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(node + "|" + host + "|" + name)
+	return nil
+}
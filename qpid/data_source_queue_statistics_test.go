@@ -0,0 +1,46 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceQueueStatistics(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceQueueCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName, testAcceptanceQueueName),
+		Steps: []resource.TestStep{
+			{
+				// statistics of non-existing queue fail
+				Config: `
+data "qpid_queue_statistics" "missing" {
+    name = "missing"
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+}
+`,
+				ExpectError: regexp.MustCompile("queue 'missing' is not found"),
+			},
+			{
+				// statistics of new queue show empty queue without consumers
+				Config: testAcceptanceQueueConfigMinimal + `
+data "qpid_queue_statistics" "statistics" {
+    name = ` + testAcceptanceQueueResource + `.name
+    virtual_host_node = ` + testAcceptanceQueueResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceQueueResource + `.virtual_host
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qpid_queue_statistics.statistics", "queue_depth_messages", "0"),
+					resource.TestCheckResourceAttr("data.qpid_queue_statistics.statistics", "queue_depth_bytes", "0"),
+					resource.TestCheckResourceAttr("data.qpid_queue_statistics.statistics", "consumer_count", "0"),
+					resource.TestCheckResourceAttr("data.qpid_queue_statistics.statistics", "total_enqueued_messages", "0"),
+					resource.TestCheckResourceAttr("data.qpid_queue_statistics.statistics", "statistics.queueDepthMessages", "0"),
+				),
+			},
+		},
+	})
+}
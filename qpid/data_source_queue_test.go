@@ -0,0 +1,49 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceQueue(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceQueueCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName, testAcceptanceQueueName),
+		Steps: []resource.TestStep{
+			{
+				// data source of non-existing object fails
+				Config: `
+data "` + testAcceptanceQueueResourceName + `" "missing" {
+    name = "missing"
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+}
+`,
+				ExpectError: regexp.MustCompile("queue 'missing' is not found"),
+			},
+			{
+				// data source exposes attributes of existing object
+				Config: testAcceptanceQueueConfigMinimal + testAcceptanceDataSourceQueueConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(testAcceptanceDataSourceQueue, "id", testAcceptanceQueueResource, "id"),
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceQueue, "type", "standard"),
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceQueue, "durable", "true"),
+					// effective values of attributes not set on the queue are exposed
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceQueue, "overflow_policy", "NONE"),
+				),
+			},
+		},
+	})
+}
+
+const testAcceptanceDataSourceQueue = "data." + testAcceptanceQueueResource
+const testAcceptanceDataSourceQueueConfig = `
+data "` + testAcceptanceQueueResourceName + `" "` + testAcceptanceQueueName + `" {
+    name = ` + testAcceptanceQueueResource + `.name
+    virtual_host_node = ` + testAcceptanceQueueResource + `.virtual_host_node
+    virtual_host = ` + testAcceptanceQueueResource + `.virtual_host
+}
+`
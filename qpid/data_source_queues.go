@@ -0,0 +1,27 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceQueues() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceQueuesRead,
+		Schema: virtualHostObjectsDataSourceSchema(resourceQueue().Schema, "queues"),
+	}
+}
+
+func dataSourceQueuesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+
+	// effective values are filtered, thus, the queues can be selected by default values as well
+	queues, err := client.List("queue", []string{node, host}, &QueryOptions{Actuals: false})
+	if err != nil {
+		return err
+	}
+
+	return applyVirtualHostObjectsDataSourceAttributes(d, dataSourceQueues().Schema, "queues", queues)
+}
@@ -0,0 +1,96 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"strings"
+	"testing"
+)
+
+func TestAcceptanceDataSourceQueues(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceQueueCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName, testAcceptanceQueueName),
+		Steps: []resource.TestStep{
+			{
+				// create queues, the data sources are evaluated after creation of queues on the next step
+				Config: testAcceptanceDataSourceQueuesConfig,
+			},
+			{
+				// list queues filtered by name regex, type and attribute values
+				Config: testAcceptanceDataSourceQueuesConfig + `
+data "qpid_queues" "orders" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    name_regex = "^orders\\."
+}
+
+data "qpid_queues" "redelivered_orders" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    name_regex = "^orders\\."
+    type = "standard"
+    attributes = {
+        durable = "true"
+        maximum_delivery_attempts = "5"
+    }
+}
+
+data "qpid_queues" "none" {
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    type = "lvq"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.qpid_queues.orders", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.qpid_queues.orders", "names.0", "orders.eu"),
+					resource.TestCheckResourceAttr("data.qpid_queues.orders", "names.1", "orders.us"),
+					resource.TestCheckResourceAttr("data.qpid_queues.orders", "queues.#", "2"),
+					resource.TestCheckResourceAttr("data.qpid_queues.orders", "queues.0.name", "orders.eu"),
+					resource.TestCheckResourceAttrPair("data.qpid_queues.orders", "queues.0.id", "qpid_queue.orders_eu", "id"),
+					resource.TestCheckResourceAttr("data.qpid_queues.redelivered_orders", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.qpid_queues.redelivered_orders", "names.0", "orders.us"),
+					resource.TestCheckResourceAttr("data.qpid_queues.redelivered_orders", "queues.0.maximum_delivery_attempts", "5"),
+					resource.TestCheckResourceAttr("data.qpid_queues.none", "names.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testAcceptanceDataSourceQueuesConfig = testAcceptanceQueueConfigMinimal + `
+resource "qpid_queue" "orders_eu" {
+    name = "orders.eu"
+    depends_on = [` + testAcceptanceVirtualHostResource + `]
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    type = "standard"
+}
+
+resource "qpid_queue" "orders_us" {
+    name = "orders.us"
+    depends_on = [` + testAcceptanceVirtualHostResource + `]
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+    virtual_host = "` + testAcceptanceVirtualHostName + `"
+    type = "standard"
+    maximum_delivery_attempts = 5
+}
+`
+
+func TestDataSourceQueuesInvalidNameRegex(t *testing.T) {
+	raw := map[string]interface{}{"virtual_host_node": "node", "virtual_host": "host", "name_regex": "orders.(eu"}
+	_, errs := dataSourceQueues().Validate(terraform.NewResourceConfigRaw(raw))
+	if len(errs) == 0 {
+		t.Errorf("invalid name regex passes validation")
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceQueues().Schema, raw)
+	err := applyVirtualHostObjectsDataSourceAttributes(d, dataSourceQueues().Schema, "queues", &[]map[string]interface{}{{"name": "orders.eu"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid name_regex 'orders.(eu'") {
+		t.Errorf("unexpected error applying invalid name regex: %v", err)
+	}
+}
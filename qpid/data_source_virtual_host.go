@@ -0,0 +1,42 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceVirtualHost() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceVirtualHostRead,
+		Schema: dataSourceSchema(resourceVirtualHost().Schema, "name", "virtual_host_node"),
+	}
+}
+
+func dataSourceVirtualHostRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	node := d.Get("virtual_host_node").(string)
+	attributes, err := client.Get("virtualhost", []string{node, name}, &QueryOptions{Actuals: false})
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("virtual host '%s' is not found on virtual host node '%s'", name, node)
+		}
+		return err
+	}
+
+	schemaMap := dataSourceVirtualHost().Schema
+	err = applyDataSourceAttributes(d, schemaMap, attributes, "node_auto_creation_policy")
+	if err != nil {
+		return err
+	}
+
+	if value, attributeSet := (*attributes)["nodeAutoCreationPolicies"]; attributeSet && value != nil {
+		value, err = toDataSourceValue(schemaMap["node_auto_creation_policy"], value)
+		if err != nil {
+			return fmt.Errorf("unexpected value of node_auto_creation_policy: %v", err)
+		}
+		return d.Set("node_auto_creation_policy", value)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package qpid
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceVirtualHostNode() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceVirtualHostNodeRead,
+		Schema: dataSourceSchema(resourceVirtualHostNode().Schema, "name"),
+	}
+}
+
+func dataSourceVirtualHostNodeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*Client)
+
+	name := d.Get("name").(string)
+	attributes, err := client.Get("virtualhostnode", []string{name}, &QueryOptions{Actuals: false})
+	if err != nil {
+		if IsNotFound(err) {
+			return fmt.Errorf("virtual host node '%s' is not found", name)
+		}
+		return err
+	}
+
+	return applyDataSourceAttributes(d, dataSourceVirtualHostNode().Schema, attributes)
+}
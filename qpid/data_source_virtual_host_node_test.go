@@ -0,0 +1,42 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceVirtualHostNode(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceVirtualHostNodeCheckDestroy(testAcceptanceVirtualHostNodeName),
+		Steps: []resource.TestStep{
+			{
+				// data source of non-existing object fails
+				Config: `
+data "` + testAcceptanceVirtualHostNodeResourceName + `" "missing" {
+    name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile("virtual host node 'missing' is not found"),
+			},
+			{
+				// data source exposes attributes of existing object
+				Config: testAcceptanceVirtualHostNodeConfigMinimal + testAcceptanceDataSourceVirtualHostNodeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(testAcceptanceDataSourceVirtualHostNode, "id", testAcceptanceVirtualHostNodeResource, "id"),
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceVirtualHostNode, "type", "JSON"),
+				),
+			},
+		},
+	})
+}
+
+const testAcceptanceDataSourceVirtualHostNode = "data." + testAcceptanceVirtualHostNodeResource
+const testAcceptanceDataSourceVirtualHostNodeConfig = `
+data "` + testAcceptanceVirtualHostNodeResourceName + `" "` + testAcceptanceVirtualHostNodeName + `" {
+    name = ` + testAcceptanceVirtualHostNodeResource + `.name
+}
+`
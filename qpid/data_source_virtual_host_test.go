@@ -0,0 +1,44 @@
+package qpid
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAcceptanceDataSourceVirtualHost(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAcceptancePreCheck(t) },
+		Providers:    testAcceptanceProviders,
+		CheckDestroy: testAcceptanceVirtualHostCheckDestroy(testAcceptanceVirtualHostNodeName, testAcceptanceVirtualHostName),
+		Steps: []resource.TestStep{
+			{
+				// data source of non-existing object fails
+				Config: `
+data "` + testAcceptanceVirtualHostResourceName + `" "missing" {
+    name = "missing"
+    virtual_host_node = "` + testAcceptanceVirtualHostNodeName + `"
+}
+`,
+				ExpectError: regexp.MustCompile("virtual host 'missing' is not found"),
+			},
+			{
+				// data source exposes attributes of existing object
+				Config: testAcceptanceVirtualHostConfigMinimal + testAcceptanceDataSourceVirtualHostConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(testAcceptanceDataSourceVirtualHost, "id", testAcceptanceVirtualHostResource, "id"),
+					resource.TestCheckResourceAttr(testAcceptanceDataSourceVirtualHost, "type", "BDB"),
+				),
+			},
+		},
+	})
+}
+
+const testAcceptanceDataSourceVirtualHost = "data." + testAcceptanceVirtualHostResource
+const testAcceptanceDataSourceVirtualHostConfig = `
+data "` + testAcceptanceVirtualHostResourceName + `" "` + testAcceptanceVirtualHostName + `" {
+    name = ` + testAcceptanceVirtualHostResource + `.name
+    virtual_host_node = ` + testAcceptanceVirtualHostResource + `.virtual_host_node
+}
+`
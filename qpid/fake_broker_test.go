@@ -0,0 +1,743 @@
+package qpid
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The acceptance tests can be run without broker against the in-memory fake of the broker management REST API
+// by setting the environment variable QPID_FAKE_BROKER, for example
+//    make testfake
+//
+// The fake implements the configured object hierarchy with generated ids, parent existence checks,
+// actual and effective attribute values, exchange bindings and the common operations.
+// It does not validate the attribute values and does not start the messaging protocols.
+
+const (
+	fakeBrokerUsername     = "admin"
+	fakeBrokerPassword     = "admin"
+	fakeBrokerModelVersion = "7.1"
+
+	fakeBrokerSessionCookie = "JSESSIONID"
+)
+
+// startFakeBrokerForAcceptanceTests starts the fake broker and points the acceptance tests to it
+func startFakeBrokerForAcceptanceTests() *fakeBroker {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	os.Setenv("QPID_ENDPOINT", broker.URL())
+	os.Setenv("QPID_USERNAME", fakeBrokerUsername)
+	os.Setenv("QPID_PASSWORD", fakeBrokerPassword)
+	os.Unsetenv("QPID_MODEL_VERSION")
+	os.Unsetenv("QPID_ENDPOINTS")
+	broker.metadata = fakeBrokerMetadata
+	return broker
+}
+
+// fakeBrokerMetadata describes queue and exchange types for the acceptance tests, thus, their plans are verified
+// against the broker capabilities. The attributes common to all types are omitted, as they are not verified.
+var fakeBrokerMetadata = map[string]interface{}{
+	"Queue": map[string]interface{}{
+		"standard": fakeBrokerTypeMetadata(map[string]interface{}{
+			"overflowPolicy": map[string]interface{}{
+				"type":        "OverflowPolicy",
+				"validValues": []interface{}{"NONE", "RING", "PRODUCER_FLOW_CONTROL", "FLOW_TO_DISK", "REJECT"},
+			},
+		}),
+		"lvq":      fakeBrokerTypeMetadata(map[string]interface{}{"lvqKey": map[string]interface{}{"type": "String"}}),
+		"priority": fakeBrokerTypeMetadata(map[string]interface{}{"priorities": map[string]interface{}{"type": "Integer"}}),
+		"sorted":   fakeBrokerTypeMetadata(map[string]interface{}{"sortKey": map[string]interface{}{"type": "String"}}),
+	},
+	"Exchange": map[string]interface{}{
+		"direct":  fakeBrokerExchangeMetadata,
+		"topic":   fakeBrokerExchangeMetadata,
+		"fanout":  fakeBrokerExchangeMetadata,
+		"headers": fakeBrokerExchangeMetadata,
+	},
+}
+
+var fakeBrokerExchangeMetadata = fakeBrokerTypeMetadata(map[string]interface{}{
+	"unroutableMessageBehaviour": map[string]interface{}{
+		"type":        "UnroutableMessageBehaviour",
+		"validValues": []interface{}{"REJECT", "DISCARD"},
+	},
+})
+
+func fakeBrokerTypeMetadata(attributes map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"attributes": attributes}
+}
+
+// fakeBrokerHierarchy maps categories of configured objects to the categories of their parents
+var fakeBrokerHierarchy = map[string]string{
+	"virtualhostnode":                  "broker",
+	"virtualhost":                      "virtualhostnode",
+	"queue":                            "virtualhost",
+	"exchange":                         "virtualhost",
+	"virtualhostlogger":                "virtualhost",
+	"virtualhostloginclusionrule":      "virtualhostlogger",
+	"virtualhostaccesscontrolprovider": "virtualhost",
+	"port":                             "broker",
+	"virtualhostalias":                 "port",
+	"keystore":                         "broker",
+	"truststore":                       "broker",
+	"authenticationprovider":           "broker",
+	"user":                             "authenticationprovider",
+	"groupprovider":                    "broker",
+	"group":                            "groupprovider",
+	"groupmember":                      "group",
+	"accesscontrolprovider":            "broker",
+	"brokerlogger":                     "broker",
+	"brokerloginclusionrule":           "brokerlogger",
+	"plugin":                           "broker",
+}
+
+// fakeBrokerDefaults are effective values of attributes which are not set
+var fakeBrokerDefaults = map[string]map[string]interface{}{
+	"queue": {
+		"type":                          "standard",
+		"exclusive":                     "NONE",
+		"messageDurability":             "DEFAULT",
+		"overflowPolicy":                "NONE",
+		"maximumQueueDepthBytes":        -1.0,
+		"maximumQueueDepthMessages":     -1.0,
+		"maximumDeliveryAttempts":       0.0,
+		"holdOnPublishEnabled":          false,
+		"ensureNondestructiveConsumers": false,
+		"noLocal":                       false,
+	},
+	"exchange": {
+		"unroutableMessageBehaviour": "DISCARD",
+	},
+	"broker": {
+		"buildVersion":                     "fake",
+		"operatingSystem":                  "Linux",
+		"platform":                         "Java",
+		"processPid":                       1.0,
+		"numberOfAvailableProcessors":      4.0,
+		"supportedVirtualHostNodeTypes":    []interface{}{"JSON", "Memory"},
+		"supportedVirtualHostTypes":        []interface{}{"BDB", "Memory"},
+		"supportedAuthenticationProviders": []interface{}{"Anonymous", "Plain"},
+	},
+}
+
+var fakeBrokerStatistics = map[string]map[string]interface{}{
+	"queue": {
+		"queueDepthMessages":         0.0,
+		"queueDepthBytes":            0.0,
+		"consumerCount":              0.0,
+		"oldestMessageAge":           0.0,
+		"totalEnqueuedMessages":      0.0,
+		"totalEnqueuedBytes":         0.0,
+		"totalDequeuedMessages":      0.0,
+		"totalDequeuedBytes":         0.0,
+		"unacknowledgedMessages":     0.0,
+		"availableMessages":          0.0,
+		"persistentEnqueuedBytes":    0.0,
+		"persistentDequeuedBytes":    0.0,
+		"bindingCount":               0.0,
+		"consumerCountWithCredit":    0.0,
+		"persistentEnqueuedMessages": 0.0,
+	},
+	"virtualhost": {
+		"queueCount":      0.0,
+		"exchangeCount":   0.0,
+		"connectionCount": 0.0,
+		"bytesIn":         0.0,
+		"bytesOut":        0.0,
+		"messagesIn":      0.0,
+		"messagesOut":     0.0,
+	},
+	"broker": {
+		"bytesIn":     0.0,
+		"bytesOut":    0.0,
+		"messagesIn":  0.0,
+		"messagesOut": 0.0,
+	},
+}
+
+// fakeBroker is in-memory implementation of Qpid Broker-J management REST API
+type fakeBroker struct {
+	server       *httptest.Server
+	mutex        sync.Mutex
+	username     string
+	password     string
+	modelVersion string
+	root         *fakeObject
+	sequence     int
+
+	// metadata served by /service/metadata, the service is not available when nil
+	metadata map[string]interface{}
+
+	sessionMutex    sync.Mutex
+	sessions        map[string]bool
+	authentications int
+}
+
+// fakeObject is a configured object of the fake broker
+type fakeObject struct {
+	category   string
+	attributes map[string]interface{}
+	parent     *fakeObject
+	children   map[string][]*fakeObject
+	bindings   []map[string]interface{}
+}
+
+func newFakeBroker(username string, password string) *fakeBroker {
+	broker := &fakeBroker{
+		username:     username,
+		password:     password,
+		modelVersion: fakeBrokerModelVersion,
+		sessions:     map[string]bool{},
+	}
+	broker.root = broker.newObject("broker", nil, map[string]interface{}{"name": "Broker"})
+	broker.server = httptest.NewServer(broker)
+	return broker
+}
+
+// URL returns the base URL of the fake broker
+func (b *fakeBroker) URL() string {
+	return b.server.URL
+}
+
+// Close shuts down the fake broker
+func (b *fakeBroker) Close() {
+	b.server.Close()
+}
+
+func (b *fakeBroker) newObject(category string, parent *fakeObject, attributes map[string]interface{}) *fakeObject {
+	b.sequence++
+	now := float64(time.Now().UnixNano() / int64(time.Millisecond))
+	attributes["id"] = fmt.Sprintf("00000000-0000-0000-0000-%012d", b.sequence)
+	attributes["createdBy"] = b.username
+	attributes["createdTime"] = now
+	attributes["lastUpdatedBy"] = b.username
+	attributes["lastUpdatedTime"] = now
+	object := &fakeObject{
+		category:   category,
+		attributes: attributes,
+		parent:     parent,
+		children:   map[string][]*fakeObject{},
+	}
+	if parent != nil {
+		parent.children[category] = append(parent.children[category], object)
+	}
+	return object
+}
+
+// Authentications returns number of requests authenticated with credentials rather than with the session
+func (b *fakeBroker) Authentications() int {
+	b.sessionMutex.Lock()
+	defer b.sessionMutex.Unlock()
+	return b.authentications
+}
+
+// ExpireSessions invalidates all management sessions
+func (b *fakeBroker) ExpireSessions() {
+	b.sessionMutex.Lock()
+	defer b.sessionMutex.Unlock()
+	b.sessions = map[string]bool{}
+}
+
+// authenticate accepts requests with valid session cookie or credentials, creating a new session for the latter
+func (b *fakeBroker) authenticate(w http.ResponseWriter, r *http.Request) bool {
+	b.sessionMutex.Lock()
+	defer b.sessionMutex.Unlock()
+
+	if cookie, err := r.Cookie(fakeBrokerSessionCookie); err == nil && b.sessions[cookie.Value] {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok || username != b.username || password != b.password {
+		return false
+	}
+
+	b.authentications++
+	session := fmt.Sprintf("session-%d", b.authentications)
+	b.sessions[session] = true
+	http.SetCookie(w, &http.Cookie{Name: fakeBrokerSessionCookie, Value: session, Path: "/", HttpOnly: true})
+	return true
+}
+
+// fakeGzipResponseWriter compresses the response body
+type fakeGzipResponseWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w *fakeGzipResponseWriter) Write(data []byte) (int, error) {
+	return w.writer.Write(data)
+}
+
+func (b *fakeBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := &fakeGzipResponseWriter{ResponseWriter: w, writer: gzip.NewWriter(w)}
+		defer gz.writer.Close()
+		w = gz
+	}
+
+	if !b.authenticate(w, r) {
+		writeFakeBrokerError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if r.URL.Path == "/service/metadata" && b.metadata != nil {
+		writeFakeBrokerResponse(w, http.StatusOK, b.metadata)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	if len(segments) < 3 || segments[0] != "api" || !b.supportsModelVersion(segments[1]) {
+		writeFakeBrokerError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	category := segments[2]
+	names := make([]string, len(segments)-3)
+	for i, segment := range segments[3:] {
+		name, err := url.PathUnescape(segment)
+		if err != nil {
+			writeFakeBrokerError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		names[i] = name
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if category == "broker" {
+		b.serveObject(w, r, b.root, names)
+		return
+	}
+
+	parentCategories := fakeBrokerAncestors(category)
+	if parentCategories == nil {
+		writeFakeBrokerError(w, http.StatusNotFound, fmt.Sprintf("Unknown category '%s'", category))
+		return
+	}
+
+	depth := len(parentCategories)
+	switch {
+	case len(names) < depth && r.Method == http.MethodGet:
+		b.serveList(w, r, category, names)
+	case len(names) == depth && r.Method == http.MethodGet:
+		if b.find(parentCategories, names) == nil {
+			writeFakeBrokerError(w, http.StatusNotFound, "Parent object not found")
+			return
+		}
+		b.serveList(w, r, category, names)
+	case len(names) == depth && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		b.create(w, r, category, parentCategories, names)
+	case len(names) > depth:
+		object := b.find(append(parentCategories, category), names[:depth+1])
+		if object == nil {
+			writeFakeBrokerError(w, http.StatusNotFound, fmt.Sprintf("%s '%s' not found", category, names[depth]))
+			return
+		}
+		b.serveObject(w, r, object, names[depth+1:])
+	default:
+		writeFakeBrokerError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (b *fakeBroker) supportsModelVersion(version string) bool {
+	if version == "latest" {
+		return true
+	}
+	major, minor, err := parseModelVersion(version)
+	if err != nil || !strings.HasPrefix(version, "v") {
+		return false
+	}
+	brokerMajor, brokerMinor, _ := parseModelVersion(b.modelVersion)
+	return major < brokerMajor || (major == brokerMajor && minor <= brokerMinor)
+}
+
+// fakeBrokerAncestors returns categories of ancestors below the broker, nil is returned for unknown category
+func fakeBrokerAncestors(category string) []string {
+	parent, known := fakeBrokerHierarchy[category]
+	if !known {
+		return nil
+	}
+
+	ancestors := []string{}
+	for parent != "broker" {
+		ancestors = append([]string{parent}, ancestors...)
+		parent = fakeBrokerHierarchy[parent]
+	}
+	return ancestors
+}
+
+// find returns the object with given categories and names of the object and its ancestors
+func (b *fakeBroker) find(categories []string, names []string) *fakeObject {
+	object := b.root
+	for i, name := range names {
+		object = object.child(categories[i], name)
+		if object == nil {
+			return nil
+		}
+	}
+	return object
+}
+
+func (o *fakeObject) child(category string, name string) *fakeObject {
+	for _, child := range o.children[category] {
+		if child.attributes["name"] == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (o *fakeObject) name() string {
+	name, _ := o.attributes["name"].(string)
+	return name
+}
+
+// descendants returns objects of given category under the object, whose ancestors match given names
+func (o *fakeObject) descendants(categories []string, names []string) []*fakeObject {
+	if len(categories) == 1 {
+		return o.children[categories[0]]
+	}
+
+	result := []*fakeObject{}
+	for _, child := range o.children[categories[0]] {
+		if len(names) == 0 || child.name() == names[0] {
+			var remaining []string
+			if len(names) > 0 {
+				remaining = names[1:]
+			}
+			result = append(result, child.descendants(categories[1:], remaining)...)
+		}
+	}
+	return result
+}
+
+func (b *fakeBroker) serveList(w http.ResponseWriter, r *http.Request, category string, names []string) {
+	query := r.URL.Query()
+	objects := b.root.descendants(append(fakeBrokerAncestors(category), category), names)
+	result := make([]map[string]interface{}, len(objects))
+	for i, object := range objects {
+		result[i] = object.representation(query)
+	}
+	writeFakeBrokerResponse(w, http.StatusOK, result)
+}
+
+func (b *fakeBroker) create(w http.ResponseWriter, r *http.Request, category string, parentCategories []string, names []string) {
+	parent := b.find(parentCategories, names)
+	if parent == nil {
+		writeFakeBrokerError(w, http.StatusNotFound, "Parent object not found")
+		return
+	}
+
+	attributes, err := readFakeBrokerAttributes(r)
+	if err != nil {
+		writeFakeBrokerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name, ok := attributes["name"].(string)
+	if !ok || name == "" {
+		writeFakeBrokerError(w, http.StatusUnprocessableEntity, "Mandatory attribute name not supplied")
+		return
+	}
+	if parent.child(category, name) != nil {
+		writeFakeBrokerError(w, http.StatusConflict, fmt.Sprintf("Object with name '%s' already exists", name))
+		return
+	}
+
+	for key, value := range attributes {
+		if value == nil {
+			delete(attributes, key)
+		}
+	}
+
+	object := b.newObject(category, parent, attributes)
+	if category == "virtualhost" {
+		for _, exchangeType := range []string{"direct", "fanout", "headers", "topic"} {
+			exchangeName := "amq." + exchangeType
+			if exchangeType == "headers" {
+				exchangeName = "amq.match"
+			}
+			b.newObject("exchange", object, map[string]interface{}{"name": exchangeName, "type": exchangeType, "durable": true})
+		}
+	}
+
+	w.Header().Set("Location", r.URL.Path+"/"+url.PathEscape(name))
+	writeFakeBrokerResponse(w, http.StatusCreated, object.representation(url.Values{}))
+}
+
+func (b *fakeBroker) serveObject(w http.ResponseWriter, r *http.Request, object *fakeObject, operation []string) {
+	if len(operation) == 1 {
+		b.invoke(w, r, object, operation[0])
+		return
+	}
+	if len(operation) > 1 {
+		writeFakeBrokerError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeBrokerResponse(w, http.StatusOK, object.representation(r.URL.Query()))
+	case http.MethodPost, http.MethodPut:
+		attributes, err := readFakeBrokerAttributes(r)
+		if err != nil {
+			writeFakeBrokerError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if name, ok := attributes["name"]; ok && name != object.name() {
+			writeFakeBrokerError(w, http.StatusUnprocessableEntity, "Changing the name is not supported")
+			return
+		}
+		for key, value := range attributes {
+			if value == nil {
+				delete(object.attributes, key)
+			} else {
+				object.attributes[key] = value
+			}
+		}
+		object.attributes["lastUpdatedTime"] = float64(time.Now().UnixNano() / int64(time.Millisecond))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if object.parent == nil {
+			writeFakeBrokerError(w, http.StatusUnprocessableEntity, "Broker cannot be deleted")
+			return
+		}
+		siblings := object.parent.children[object.category]
+		for i, sibling := range siblings {
+			if sibling == object {
+				object.parent.children[object.category] = append(siblings[:i:i], siblings[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeFakeBrokerError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (b *fakeBroker) invoke(w http.ResponseWriter, r *http.Request, object *fakeObject, operation string) {
+	parameters := map[string]interface{}{}
+	if r.Method == http.MethodPost {
+		var err error
+		parameters, err = readFakeBrokerAttributes(r)
+		if err != nil {
+			writeFakeBrokerError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	switch {
+	case object.category == "exchange" && operation == "bind":
+		writeFakeBrokerResponse(w, http.StatusOK, object.bind(parameters))
+	case object.category == "exchange" && operation == "unbind":
+		writeFakeBrokerResponse(w, http.StatusOK, object.unbind(parameters))
+	case object.category == "queue" && operation == "clearQueue":
+		writeFakeBrokerResponse(w, http.StatusOK, 0)
+	case object.category == "queue" && (operation == "moveMessages" || operation == "copyMessages"):
+		if object.parent.child("queue", fmt.Sprintf("%v", parameters["destination"])) == nil {
+			writeFakeBrokerError(w, http.StatusUnprocessableEntity, "Destination queue not found")
+			return
+		}
+		writeFakeBrokerResponse(w, http.StatusOK, []interface{}{})
+	case operation == "getStatistics" && fakeBrokerStatistics[object.category] != nil:
+		writeFakeBrokerResponse(w, http.StatusOK, object.statistics())
+	case operation == "resetStatistics" && fakeBrokerStatistics[object.category] != nil:
+		w.WriteHeader(http.StatusOK)
+	case operation == "extractConfig" && (object.category == "virtualhost" || object.category == "broker"):
+		writeFakeBrokerResponse(w, http.StatusOK, object.representation(url.Values{"actuals": {"true"}, "depth": {"10"}}))
+	default:
+		writeFakeBrokerError(w, http.StatusNotFound, fmt.Sprintf("No such operation '%s' on '%s'", operation, object.category))
+	}
+}
+
+func (o *fakeObject) bind(parameters map[string]interface{}) bool {
+	destination, _ := parameters["destination"].(string)
+	bindingKey, _ := parameters["bindingKey"].(string)
+	replace, _ := parameters["replaceExistingArguments"].(bool)
+	if o.parent.child("queue", destination) == nil && o.parent.child("exchange", destination) == nil {
+		return false
+	}
+
+	arguments, _ := parameters["arguments"].(map[string]interface{})
+	for _, binding := range o.bindings {
+		if binding["destination"] == destination && binding["bindingKey"] == bindingKey {
+			if !replace {
+				return false
+			}
+			setFakeBindingArguments(binding, arguments)
+			return true
+		}
+	}
+
+	binding := map[string]interface{}{
+		"name":        bindingKey,
+		"bindingKey":  bindingKey,
+		"destination": destination,
+		"type":        "binding",
+	}
+	setFakeBindingArguments(binding, arguments)
+	o.bindings = append(o.bindings, binding)
+	return true
+}
+
+func setFakeBindingArguments(binding map[string]interface{}, arguments map[string]interface{}) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	binding["arguments"] = arguments
+}
+
+func (o *fakeObject) unbind(parameters map[string]interface{}) bool {
+	for i, binding := range o.bindings {
+		if binding["destination"] == parameters["destination"] && binding["bindingKey"] == parameters["bindingKey"] {
+			o.bindings = append(o.bindings[:i:i], o.bindings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (o *fakeObject) statistics() map[string]interface{} {
+	statistics := map[string]interface{}{}
+	for key, value := range fakeBrokerStatistics[o.category] {
+		statistics[key] = value
+	}
+	switch o.category {
+	case "queue":
+		statistics["bindingCount"] = float64(o.countBindings())
+	case "virtualhost":
+		statistics["queueCount"] = float64(len(o.children["queue"]))
+		statistics["exchangeCount"] = float64(len(o.children["exchange"]))
+	}
+	return statistics
+}
+
+func (o *fakeObject) countBindings() int {
+	count := 0
+	for _, exchange := range o.parent.children["exchange"] {
+		for _, binding := range exchange.bindings {
+			if binding["destination"] == o.name() {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// representation returns the object attributes as returned by the broker for given query parameters
+func (o *fakeObject) representation(query url.Values) map[string]interface{} {
+	actuals, _ := strconv.ParseBool(query.Get("actuals"))
+	depth, _ := strconv.Atoi(query.Get("depth"))
+
+	result := map[string]interface{}{}
+	if !actuals {
+		for key, value := range fakeBrokerDefaults[o.category] {
+			result[key] = value
+		}
+		result["durable"] = true
+		result["lifetimePolicy"] = "PERMANENT"
+		result["desiredState"] = "ACTIVE"
+		result["state"] = "ACTIVE"
+	}
+
+	for key, value := range o.attributes {
+		result[key] = value
+	}
+
+	if !actuals {
+		for key, value := range result {
+			if s, ok := value.(string); ok {
+				result[key] = o.resolve(s)
+			}
+		}
+		if o.category == "exchange" {
+			bindings := make([]interface{}, len(o.bindings))
+			for i, binding := range o.bindings {
+				bindings[i] = binding
+			}
+			result["bindings"] = bindings
+		}
+		if o.category == "broker" {
+			result["modelVersion"] = fakeBrokerModelVersion
+			result["productVersion"] = fakeBrokerModelVersion + ".0"
+		}
+		if fakeBrokerStatistics[o.category] != nil {
+			result["statistics"] = o.statistics()
+		}
+	}
+
+	if depth > 0 {
+		childQuery := url.Values{"actuals": {strconv.FormatBool(actuals)}, "depth": {strconv.Itoa(depth - 1)}}
+		for category, children := range o.children {
+			if len(children) > 0 {
+				list := make([]interface{}, len(children))
+				for i, child := range children {
+					list[i] = child.representation(childQuery)
+				}
+				result[category+"s"] = list
+			}
+		}
+	}
+	return result
+}
+
+// resolve replaces references to context variables of the object and its ancestors
+func (o *fakeObject) resolve(value string) string {
+	for object := o; object != nil; object = object.parent {
+		if context, ok := object.attributes["context"].(map[string]interface{}); ok {
+			for name, variable := range context {
+				value = strings.Replace(value, "${"+name+"}", fmt.Sprintf("%v", variable), -1)
+			}
+		}
+	}
+	return value
+}
+
+func readFakeBrokerAttributes(r *http.Request) (map[string]interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]interface{}{}
+	if len(body) == 0 {
+		return attributes, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err = decoder.Decode(&attributes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse request body: %v", err)
+	}
+	return attributes, nil
+}
+
+func writeFakeBrokerResponse(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("[ERROR] fake broker: unable to encode response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeFakeBrokerError(w http.ResponseWriter, status int, message string) {
+	writeFakeBrokerResponse(w, status, map[string]interface{}{"errorMessage": message})
+}
@@ -0,0 +1,33 @@
+package qpid
+
+import (
+	"net/http"
+)
+
+// HeaderTransport is a http.RoundTripper adding configured headers to every request
+type HeaderTransport struct {
+	transport http.RoundTripper
+	headers   map[string]string
+}
+
+// NewHeaderTransport creates HeaderTransport adding given headers to requests sent via given transport
+func NewHeaderTransport(transport http.RoundTripper, headers map[string]string) *HeaderTransport {
+	return &HeaderTransport{
+		transport: transport,
+		headers:   headers,
+	}
+}
+
+// RoundTrip sends a copy of the request with added headers
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	for name, value := range t.headers {
+		r.Header.Set(name, value)
+	}
+
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(r)
+}
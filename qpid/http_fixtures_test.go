@@ -0,0 +1,364 @@
+package qpid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The broker interactions of the acceptance tests can be recorded into fixture files and replayed without broker.
+//
+// The interactions are recorded from the acceptance tests run against the broker with QPID_HTTP_FIXTURES=record
+//    make testrecord
+//
+// The recorded interactions are replayed with QPID_HTTP_FIXTURES=replay
+//    make testreplay
+//
+// The fixtures are stored in testdata/fixtures as a file per test. Passwords and tokens are not recorded,
+// and values of sensitive attributes are redacted the same way as in the trace log. Only the response headers
+// used by the client are recorded, and the broker host is replaced in the recorded endpoint.
+// The tests without fixtures fail in the replay mode, unless they are marked as not replayable.
+//
+// The fixtures must be recorded against a real broker, recording against the fake broker is refused,
+// as the replay would only test the fake again.
+
+const (
+	httpFixturesRecord = "record"
+	httpFixturesReplay = "replay"
+	httpFixturesDir    = "testdata/fixtures"
+	httpFixturesHost   = "qpid-broker"
+)
+
+// recordedHeaders are the response headers stored in the fixtures, others, like Date, change with every recording
+var recordedHeaders = []string{"Content-Type"}
+
+type httpFixture struct {
+	Endpoint     string             `json:"endpoint"`
+	Username     string             `json:"username"`
+	Interactions []*httpInteraction `json:"interactions"`
+
+	mutex sync.Mutex
+}
+
+type httpInteraction struct {
+	Request  httpFixtureRequest  `json:"request"`
+	Response httpFixtureResponse `json:"response"`
+
+	replayed bool
+}
+
+type httpFixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type httpFixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// fixtureTransport records broker interactions into the fixture or replays them from the fixture
+type fixtureTransport struct {
+	mode      string
+	fixture   *httpFixture
+	transport http.RoundTripper
+}
+
+// useHTTPFixtures records or replays broker interactions of the test according to QPID_HTTP_FIXTURES
+func useHTTPFixtures(t *testing.T) {
+	mode := os.Getenv("QPID_HTTP_FIXTURES")
+	if mode == "" {
+		return
+	}
+
+	path := filepath.Join(httpFixturesDir, t.Name()+".json")
+	var fixture *httpFixture
+	switch mode {
+	case httpFixturesRecord:
+		if os.Getenv("QPID_FAKE_BROKER") != "" {
+			t.Fatalf("broker interactions must be recorded against a real broker, unset QPID_FAKE_BROKER")
+		}
+		endpoint, err := normalizeFixtureEndpoint(os.Getenv("QPID_ENDPOINT"))
+		if err != nil {
+			t.Fatalf("unable to record broker interactions: %v", err)
+		}
+		fixture = &httpFixture{Endpoint: endpoint, Username: os.Getenv("QPID_USERNAME")}
+	case httpFixturesReplay:
+		var err error
+		fixture, err = loadHTTPFixture(path)
+		if os.IsNotExist(err) {
+			t.Fatalf("no recorded broker interactions in %s, record them with 'make testrecord'", path)
+		}
+		if err != nil {
+			t.Fatalf("unable to load broker interactions from %s: %v", path, err)
+		}
+		if fixture.Username != os.Getenv("QPID_USERNAME") {
+			t.Fatalf("broker interactions in %s are recorded for user '%s', but QPID_USERNAME is '%s'", path, fixture.Username, os.Getenv("QPID_USERNAME"))
+		}
+		os.Setenv("QPID_ENDPOINT", fixture.Endpoint)
+	default:
+		t.Fatalf("unsupported QPID_HTTP_FIXTURES mode '%s', expected '%s' or '%s'", mode, httpFixturesRecord, httpFixturesReplay)
+	}
+
+	previous := transportWrapper
+	transportWrapper = func(transport http.RoundTripper) http.RoundTripper {
+		return &fixtureTransport{mode: mode, fixture: fixture, transport: transport}
+	}
+	t.Cleanup(func() {
+		transportWrapper = previous
+		if mode == httpFixturesRecord {
+			if err := fixture.save(path); err != nil {
+				t.Errorf("unable to save broker interactions into %s: %v", path, err)
+			}
+		}
+	})
+}
+
+// setUpHTTPFixturesEnvironment sets the environment of the acceptance tests replaying broker interactions.
+// The tests use the username in the configuration, thus, the user of the recording is set when QPID_USERNAME is not set.
+func setUpHTTPFixturesEnvironment() {
+	if os.Getenv("QPID_HTTP_FIXTURES") != httpFixturesReplay {
+		return
+	}
+
+	if os.Getenv("QPID_USERNAME") == "" {
+		paths, _ := filepath.Glob(filepath.Join(httpFixturesDir, "*.json"))
+		for _, path := range paths {
+			if fixture, err := loadHTTPFixture(path); err == nil {
+				os.Setenv("QPID_USERNAME", fixture.Username)
+				os.Setenv("QPID_ENDPOINT", fixture.Endpoint)
+				break
+			}
+		}
+	}
+	if os.Getenv("QPID_PASSWORD") == "" {
+		os.Setenv("QPID_PASSWORD", "replay")
+	}
+}
+
+// skipHTTPFixturesReplay skips the test which cannot be replayed
+func skipHTTPFixturesReplay(t *testing.T, reason string) {
+	if os.Getenv("QPID_HTTP_FIXTURES") == httpFixturesReplay {
+		t.Skipf("broker interactions cannot be replayed: %s", reason)
+	}
+}
+
+// normalizeFixtureEndpoint replaces the host of the endpoint, keeping the scheme and the path,
+// the replayed requests are matched without the host
+func normalizeFixtureEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint '%s': %v", endpoint, err)
+	}
+	u.Host = httpFixturesHost
+	u.User = nil
+	return u.String(), nil
+}
+
+func loadHTTPFixture(path string) (*httpFixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &httpFixture{}
+	err = json.Unmarshal(data, fixture)
+	return fixture, err
+}
+
+func (f *httpFixture) save(path string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	request := httpFixtureRequest{Method: req.Method, URL: req.URL.RequestURI()}
+	if len(body) > 0 {
+		request.Body = redactBody(body, req.Header.Get("Content-Type"))
+	}
+
+	if t.mode == httpFixturesReplay {
+		return t.fixture.replay(req, &request)
+	}
+	return t.record(req, &request)
+}
+
+func (t *fixtureTransport) record(req *http.Request, request *httpFixtureRequest) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range recordedHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	response := httpFixtureResponse{StatusCode: resp.StatusCode, Header: header}
+	if len(body) > 0 {
+		response.Body = redactBody(body, resp.Header.Get("Content-Type"))
+	}
+
+	t.fixture.mutex.Lock()
+	defer t.fixture.mutex.Unlock()
+	t.fixture.Interactions = append(t.fixture.Interactions, &httpInteraction{Request: *request, Response: response})
+	return resp, nil
+}
+
+// replay returns response of the first not replayed interaction with the same request,
+// the request body is ignored if no interaction has the same body
+func (f *httpFixture) replay(req *http.Request, request *httpFixtureRequest) (*http.Response, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var match *httpInteraction
+	for _, interaction := range f.Interactions {
+		if interaction.replayed || interaction.Request.Method != request.Method || interaction.Request.URL != request.URL {
+			continue
+		}
+		if interaction.Request.Body == request.Body {
+			match = interaction
+			break
+		}
+		if match == nil {
+			match = interaction
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no recorded interaction for %s %s", request.Method, request.URL)
+	}
+	match.replayed = true
+
+	response := match.Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        response.Header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func TestHTTPFixturesRecordAndReplay(t *testing.T) {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	defer broker.Close()
+
+	endpoint, err := normalizeFixtureEndpoint("https://user@broker.example.com:8443/context")
+	if err != nil || endpoint != "https://"+httpFixturesHost+"/context" {
+		t.Fatalf("unexpected normalized endpoint '%s': %v", endpoint, err)
+	}
+	endpoint, err = normalizeFixtureEndpoint(broker.URL())
+	if err != nil || endpoint != "http://"+httpFixturesHost {
+		t.Fatalf("unexpected normalized endpoint '%s': %v", endpoint, err)
+	}
+	fixture := &httpFixture{Endpoint: endpoint}
+	recording := &fixtureTransport{mode: httpFixturesRecord, fixture: fixture, transport: http.DefaultTransport}
+	client, err := NewClient(broker.URL(), fakeBrokerUsername, fakeBrokerPassword, "v7.1", recording)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	_, err = client.CreateAuthenticationProvider(&map[string]interface{}{"name": "provider", "type": "Plain"})
+	if err != nil {
+		t.Fatalf("unable to create authentication provider: %v", err)
+	}
+	_, err = client.CreateUser("provider", &map[string]interface{}{"name": "user", "password": "secret-password"})
+	if err != nil {
+		t.Fatalf("unable to create user: %v", err)
+	}
+	recorded, err := client.GetUser("provider", "user")
+	if err != nil {
+		t.Fatalf("unable to get user: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	err = fixture.save(path)
+	if err != nil {
+		t.Fatalf("unable to save fixture: %v", err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+	if strings.Contains(string(data), "secret-password") || strings.Contains(string(data), "Authorization") {
+		t.Fatalf("credentials are recorded: %s", data)
+	}
+	if strings.Contains(string(data), `"Date"`) || strings.Contains(string(data), `"Set-Cookie"`) || strings.Contains(string(data), broker.URL()) {
+		t.Fatalf("volatile headers or broker address are recorded: %s", data)
+	}
+
+	broker.Close()
+	loaded, err := loadHTTPFixture(path)
+	if err != nil {
+		t.Fatalf("unable to load fixture: %v", err)
+	}
+	replaying := &fixtureTransport{mode: httpFixturesReplay, fixture: loaded}
+	client, err = NewClient(loaded.Endpoint, fakeBrokerUsername, fakeBrokerPassword, "v7.1", replaying)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	_, err = client.CreateAuthenticationProvider(&map[string]interface{}{"name": "provider", "type": "Plain"})
+	if err != nil {
+		t.Fatalf("unable to replay authentication provider creation: %v", err)
+	}
+	_, err = client.CreateUser("provider", &map[string]interface{}{"name": "user", "password": "other-password"})
+	if err != nil {
+		t.Fatalf("unable to replay user creation: %v", err)
+	}
+	replayed, err := client.GetUser("provider", "user")
+	if err != nil {
+		t.Fatalf("unable to replay getting user: %v", err)
+	}
+	if (*replayed)["id"] != (*recorded)["id"] {
+		t.Fatalf("unexpected replayed user %v, expected %v", *replayed, *recorded)
+	}
+
+	_, err = client.GetUser("provider", "user")
+	if err == nil {
+		t.Fatalf("interaction is replayed more than once")
+	}
+}
@@ -0,0 +1,197 @@
+package qpid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const redacted = "<REDACTED>"
+
+// sensitiveHeaders are headers carrying credentials or session identifiers
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveParameters are names of credentials used in authentication requests and responses
+var sensitiveParameters = []string{"password", "client_secret", "response", "challenge", "access_token", "refresh_token", "id_token"}
+
+var sensitiveNames map[string]struct{}
+var sensitiveNamesOnce sync.Once
+
+// LoggingTransport is a http.RoundTripper logging requests and responses with sensitive values redacted.
+// Values of attributes marked as sensitive in any resource schema, credentials and authentication headers are redacted.
+type LoggingTransport struct {
+	transport http.RoundTripper
+}
+
+// NewLoggingTransport creates LoggingTransport logging requests sent via given transport
+func NewLoggingTransport(transport http.RoundTripper) *LoggingTransport {
+	return &LoggingTransport{transport: transport}
+}
+
+// RoundTrip sends the request and logs it together with the response
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody := "<none>"
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, err := ioutil.ReadAll(body)
+			if err == nil && len(data) > 0 {
+				requestBody = redactBody(data, req.Header.Get("Content-Type"))
+			}
+		}
+	}
+
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[TRACE] Qpid API: %s %s failed after %v: %v\nRequest headers: %s\nRequest body: %s",
+			req.Method, req.URL.RequestURI(), duration, err, redactHeaders(req.Header), requestBody)
+		return resp, err
+	}
+
+	responseBody := "<none>"
+	data, readErr := ioutil.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if readErr != nil {
+		return resp, readErr
+	}
+	if closeErr != nil {
+		return resp, closeErr
+	}
+	if len(data) > 0 {
+		responseBody = redactBody(data, resp.Header.Get("Content-Type"))
+	}
+
+	log.Printf("[TRACE] Qpid API: %s %s -> %s in %v\nRequest headers: %s\nRequest body: %s\nResponse headers: %s\nResponse body: %s",
+		req.Method, req.URL.RequestURI(), resp.Status, duration, redactHeaders(req.Header), requestBody, redactHeaders(resp.Header), responseBody)
+	return resp, nil
+}
+
+func redactHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		for _, sensitive := range sensitiveHeaders {
+			if strings.EqualFold(name, sensitive) {
+				value = redacted
+				break
+			}
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return "[" + strings.Join(parts, "; ") + "]"
+}
+
+func redactBody(data []byte, contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(data))
+		if err == nil {
+			for name := range values {
+				if isSensitiveName(name) {
+					values.Set(name, redacted)
+				}
+			}
+			return values.Encode()
+		}
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Sprintf("<%d bytes of %s>", len(data), contentType)
+	}
+
+	var result bytes.Buffer
+	encoder := json.NewEncoder(&result)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactValue(value)); err != nil {
+		return fmt.Sprintf("<%d bytes of %s>", len(data), contentType)
+	}
+	return strings.TrimSpace(result.String())
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSensitiveName(key) && item != nil {
+				result[key] = redacted
+			} else {
+				result[key] = redactValue(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redactValue(item)
+		}
+		return result
+	}
+	return value
+}
+
+func isSensitiveName(name string) bool {
+	sensitiveNamesOnce.Do(func() {
+		sensitiveNames = collectSensitiveNames()
+	})
+	if _, sensitive := sensitiveNames[name]; sensitive {
+		return true
+	}
+
+	// attributes of broker objects not managed by the provider might hold secrets as well
+	lowerCaseName := strings.ToLower(name)
+	return strings.Contains(lowerCaseName, "password") || strings.Contains(lowerCaseName, "secret")
+}
+
+// collectSensitiveNames returns names of attributes marked as sensitive in the provider schemas
+// both underscored and camel cased as well as names of credential parameters
+func collectSensitiveNames() map[string]struct{} {
+	names := arrayOfStringsToMap(sensitiveParameters)
+	provider := Provider().(*schema.Provider)
+	addSensitiveNames(names, provider.Schema)
+	for _, resource := range provider.ResourcesMap {
+		addSensitiveNames(names, resource.Schema)
+	}
+	for _, dataSource := range provider.DataSourcesMap {
+		addSensitiveNames(names, dataSource.Schema)
+	}
+	return names
+}
+
+func addSensitiveNames(names map[string]struct{}, schemaMap map[string]*schema.Schema) {
+	for key, s := range schemaMap {
+		if s.Sensitive {
+			names[key] = struct{}{}
+			names[convertToCamelCase(key)] = struct{}{}
+		}
+		if resource, ok := s.Elem.(*schema.Resource); ok {
+			addSensitiveNames(names, resource.Schema)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package qpid
+
+import (
+	"bytes"
+	"encoding/base64"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureLog returns the log output written by given function
+func captureLog(f func()) string {
+	var buffer bytes.Buffer
+	log.SetOutput(&buffer)
+	defer log.SetOutput(os.Stderr)
+	f()
+	return buffer.String()
+}
+
+func TestLoggingTransportRedactsCredentials(t *testing.T) {
+	broker := newFakeBroker(fakeBrokerUsername, "broker-secret-password")
+	defer broker.Close()
+
+	output := captureLog(func() {
+		client, err := NewClient(broker.URL(), fakeBrokerUsername, "broker-secret-password", "", NewLoggingTransport(http.DefaultTransport))
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+		_, err = client.CreateAuthenticationProvider(&map[string]interface{}{"name": "provider", "type": "Plain"})
+		if err != nil {
+			t.Fatalf("unable to create authentication provider: %v", err)
+		}
+		_, err = client.Create("user", []string{"provider"}, &map[string]interface{}{"name": "guest", "password": "user-secret-password"})
+		if err != nil {
+			t.Fatalf("unable to create user: %v", err)
+		}
+		_, err = client.Get("user", []string{"provider", "guest"}, &QueryOptions{Actuals: true})
+		if err != nil {
+			t.Fatalf("unable to get user: %v", err)
+		}
+	})
+
+	basicAuthorization := base64.StdEncoding.EncodeToString([]byte(fakeBrokerUsername + ":broker-secret-password"))
+	for _, secret := range []string{"broker-secret-password", basicAuthorization, "user-secret-password", "session-1"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("secret '%s' is logged:\n%s", secret, output)
+		}
+	}
+	for _, expected := range []string{"[TRACE] Qpid API: POST /api/latest/user/provider", `"name":"guest"`, `"password":"` + redacted + `"`, "Authorization: " + redacted, "Set-Cookie: " + redacted} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected '%s' is not logged:\n%s", expected, output)
+		}
+	}
+}
+
+func TestLoggingTransportRedactsTokens(t *testing.T) {
+	authorizationServer := newFakeAuthorizationServer("client", "client-secret-value", 3600)
+	defer authorizationServer.Close()
+	server := newBearerTokenServer(authorizationServer)
+	defer server.Close()
+
+	output := captureLog(func() {
+		transport := NewLoggingTransport(http.DefaultTransport)
+		credentials := NewOAuth2ClientCredentials(authorizationServer.URL, "client", "client-secret-value", nil, transport)
+		client, err := NewSimpleRestClient(server.URL, credentials, transport)
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+		_, err = client.GetAsMap("broker", url.Values{})
+		if err != nil {
+			t.Fatalf("unable to get broker: %v", err)
+		}
+	})
+
+	clientAuthorization := base64.StdEncoding.EncodeToString([]byte("client:client-secret-value"))
+	for _, secret := range []string{"client-secret-value", clientAuthorization, "token-1"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("secret '%s' is logged:\n%s", secret, output)
+		}
+	}
+	if !strings.Contains(output, `"access_token":"`+redacted+`"`) || !strings.Contains(output, "grant_type=client_credentials") {
+		t.Errorf("token request and response are not logged:\n%s", output)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	bodies := []struct {
+		body        string
+		contentType string
+		expected    string
+	}{
+		{
+			body:        `{"name":"store","path":"/store","keyStorePassword":"one","nested":[{"clientSecret":"two"}]}`,
+			contentType: "application/json",
+			expected:    `{"keyStorePassword":"<REDACTED>","name":"store","nested":[{"clientSecret":"<REDACTED>"}],"path":"/store"}`,
+		},
+		{
+			body:        "grant_type=password&password=three&username=user",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			expected:    "grant_type=password&password=%3CREDACTED%3E&username=user",
+		},
+		{
+			body:        "binary",
+			contentType: "application/octet-stream",
+			expected:    "<6 bytes of application/octet-stream>",
+		},
+	}
+	for _, b := range bodies {
+		if result := redactBody([]byte(b.body), b.contentType); result != b.expected {
+			t.Errorf("unexpected redacted body '%s', expected '%s'", result, b.expected)
+		}
+	}
+}
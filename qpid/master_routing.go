@@ -0,0 +1,83 @@
+package qpid
+
+import (
+	"log"
+	"net/http"
+)
+
+// sendChange sends the change of the object with given category and path with given function.
+// Changes of virtual host objects are sent to the broker where the virtual host node is the master,
+// the other changes are sent to the active endpoint. The endpoint of the master is looked up on the first
+// change of the node objects and again after the change sent to it fails or is redirected.
+func (c *Client) sendChange(category string, path []string, send func(endpoint int) (*http.Response, error)) (*http.Response, error) {
+	if _, ok := virtualHostCategories[category]; !ok || len(path) == 0 || c.restClient.EndpointCount() < 2 {
+		return send(anyEndpoint)
+	}
+
+	node := path[0]
+	endpoint := c.masterEndpoint(node)
+	resp, err := send(endpoint)
+	if endpoint == anyEndpoint || !c.isRoutingFailure(endpoint, resp, err) {
+		return resp, err
+	}
+
+	log.Printf("[DEBUG] Qpid: change of %s sent to master of virtual host node '%s' on endpoint %d failed, refreshing the role of the node: %v",
+		category, node, endpoint, describeFailure(resp, err))
+	c.forgetMaster(node)
+	if err != nil && isConnectionFailure(err) {
+		// the change is not sent yet, thus, it can be sent to the new master
+		if master := c.masterEndpoint(node); master != endpoint {
+			return send(master)
+		}
+	}
+	return resp, err
+}
+
+// masterEndpoint returns index of the endpoint of the broker where given virtual host node is the master,
+// or anyEndpoint when the master is not found
+func (c *Client) masterEndpoint(node string) int {
+	c.mastersMutex.Lock()
+	index, known := c.masters[node]
+	c.mastersMutex.Unlock()
+	if known {
+		return index
+	}
+
+	count := c.restClient.EndpointCount()
+	active := c.restClient.ActiveEndpoint()
+	options := &QueryOptions{Actuals: false}
+	for i := 0; i < count; i++ {
+		index = (active + i) % count
+		attributes, err := c.restClient.GetAsMapFromEndpoint(index, c.objectPath("virtualhostnode", []string{node}), options.Values())
+		if err != nil {
+			log.Printf("[DEBUG] Qpid: unable to get role of virtual host node '%s' on endpoint %d: %v", node, index, err)
+			continue
+		}
+
+		if role, ok := (*attributes)["role"]; ok && role == "MASTER" {
+			c.mastersMutex.Lock()
+			c.masters[node] = index
+			c.mastersMutex.Unlock()
+			return index
+		}
+	}
+
+	log.Printf("[WARN] Qpid: master of virtual host node '%s' is not found", node)
+	return anyEndpoint
+}
+
+// forgetMaster discards the cached endpoint of the master of given virtual host node
+func (c *Client) forgetMaster(node string) {
+	c.mastersMutex.Lock()
+	defer c.mastersMutex.Unlock()
+	delete(c.masters, node)
+}
+
+// isRoutingFailure returns true when the change sent to the endpoint with given index failed
+// or the response came from another endpoint after redirect
+func (c *Client) isRoutingFailure(endpoint int, resp *http.Response, err error) bool {
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	return resp.Request != nil && c.restClient.endpointIndex(resp.Request.URL) != endpoint
+}
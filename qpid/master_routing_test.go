@@ -0,0 +1,139 @@
+package qpid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeReplicationGroupMember is a broker of replication group holding the roles of its virtual host nodes.
+// Changes of the virtual host objects are only accepted for the nodes where the broker is the master.
+type fakeReplicationGroupMember struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	roles   map[string]string
+	probes  int
+	changes map[string]int
+}
+
+func newFakeReplicationGroupMember(roles map[string]string) *fakeReplicationGroupMember {
+	member := &fakeReplicationGroupMember{roles: roles, changes: map[string]int{}}
+	member.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the paths are /api/latest/<category>/<node>/...
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		member.mutex.Lock()
+		defer member.mutex.Unlock()
+
+		node := segments[3]
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet && segments[2] == "virtualhostnode" {
+			member.probes++
+			_, _ = fmt.Fprintf(w, `{"name": "%s", "role": "%s"}`, node, member.roles[node])
+			return
+		}
+		if member.roles[node] != "MASTER" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		member.changes[node]++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	return member
+}
+
+func (m *fakeReplicationGroupMember) counts(node string) (int, int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.probes, m.changes[node]
+}
+
+func TestClientRoutesChangesToMaster(t *testing.T) {
+	first := newFakeReplicationGroupMember(map[string]string{"a": "MASTER", "b": "REPLICA"})
+	defer first.Close()
+	second := newFakeReplicationGroupMember(map[string]string{"a": "REPLICA", "b": "MASTER"})
+	defer second.Close()
+
+	client, err := NewClientForEndpoints([]string{first.URL, second.URL}, nil, "", http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	// concurrent changes of the nodes with masters on different brokers
+	var wg sync.WaitGroup
+	failures := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		for _, node := range []string{"a", "b"} {
+			wg.Add(1)
+			go func(node string) {
+				defer wg.Done()
+				resp, err := client.CreateQueue(node, "host", &map[string]interface{}{"name": "queue"})
+				if err == nil && resp.StatusCode != http.StatusCreated {
+					err = fmt.Errorf("change of node '%s' is rejected: %s", node, resp.Status)
+				}
+				if err != nil {
+					failures <- err
+				}
+			}(node)
+		}
+	}
+	wg.Wait()
+	close(failures)
+	for err := range failures {
+		t.Error(err)
+	}
+
+	firstProbes, firstChanges := first.counts("a")
+	secondProbes, secondChanges := second.counts("b")
+	if firstChanges != 10 || secondChanges != 10 {
+		t.Fatalf("unexpected number of changes %d and %d, expected all changes to be sent to the masters", firstChanges, secondChanges)
+	}
+	if firstProbes+secondProbes > 2*2*10 || client.restClient.ActiveEndpoint() != 0 {
+		t.Fatalf("unexpected number of role probes %d or active endpoint %d", firstProbes+secondProbes, client.restClient.ActiveEndpoint())
+	}
+
+	// the roles are cached, thus, the next changes do not probe the roles
+	probes := firstProbes + secondProbes
+	for _, node := range []string{"a", "b"} {
+		_, err = client.UpdateQueue(node, "host", "queue", &map[string]interface{}{"durable": true})
+		if err != nil {
+			t.Fatalf("unable to update queue: %v", err)
+		}
+	}
+	firstProbes, _ = first.counts("a")
+	secondProbes, _ = second.counts("b")
+	if firstProbes+secondProbes != probes {
+		t.Fatalf("roles are probed again: %d probes, expected %d", firstProbes+secondProbes, probes)
+	}
+
+	// the roles are refreshed after the master changes
+	first.mutex.Lock()
+	first.roles["a"] = "REPLICA"
+	first.mutex.Unlock()
+	second.mutex.Lock()
+	second.roles["a"] = "MASTER"
+	second.mutex.Unlock()
+	resp, err := client.CreateQueue("a", "host", &map[string]interface{}{"name": "other"})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected result of change sent to former master %v: %v", resp, err)
+	}
+	resp, err = client.CreateQueue("a", "host", &map[string]interface{}{"name": "other"})
+	if _, changes := second.counts("a"); err != nil || resp.StatusCode != http.StatusCreated || changes != 1 {
+		t.Fatalf("change is not sent to new master %v: %v", resp, err)
+	}
+
+	// the change is resent to the new master when the former master is unreachable
+	second.mutex.Lock()
+	second.roles["a"] = "REPLICA"
+	second.mutex.Unlock()
+	first.mutex.Lock()
+	first.roles["a"] = "MASTER"
+	first.mutex.Unlock()
+	second.Close()
+	resp, err = client.CreateQueue("a", "host", &map[string]interface{}{"name": "another"})
+	if _, changes := first.counts("a"); err != nil || resp.StatusCode != http.StatusCreated || changes != 12 {
+		t.Fatalf("change is not resent to new master %v: %v", resp, err)
+	}
+}
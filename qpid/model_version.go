@@ -0,0 +1,148 @@
+package qpid
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// latestModelVersion is an alias of the newest model version supported by the broker
+const latestModelVersion = "latest"
+
+// supportedModelVersions lists model versions supported by the provider, from oldest to newest
+var supportedModelVersions = []string{"v6.0", "v6.1", "v7.0", "v7.1", "v8.0", "v9.0"}
+
+// apiRootPath is the base path of the management API, the model version is the first segment of the request paths
+const apiRootPath = "api"
+
+func apiPath(modelVersion string) string {
+	return apiRootPath + "/" + versionOrLatest(modelVersion)
+}
+
+func versionOrLatest(modelVersion string) string {
+	if modelVersion == "" {
+		return latestModelVersion
+	}
+	return modelVersion
+}
+
+// modelPath returns given path relative to the API root for the model version used in requests
+func (c *Client) modelPath(path string) string {
+	return versionOrLatest(c.modelVersion) + "/" + path
+}
+
+// objectPath returns path of the object of given category and path relative to the API root
+func (c *Client) objectPath(category string, path []string) string {
+	return c.modelPath(configuredObjectPath(category, path))
+}
+
+// ResolveModelVersion discovers the newest model version supported by both the provider and the broker
+// when model version is not set, otherwise, verifies that configured model version is supported by the broker.
+// The latest model version of the broker is only probed when the model version is not set.
+func (c *Client) ResolveModelVersion() error {
+	if c.modelVersion != "" {
+		version, err := c.getBrokerModelVersion(c.modelVersion)
+		if err != nil {
+			return fmt.Errorf("model version '%s' is not supported by the broker: %v", c.modelVersion, err)
+		}
+		if version == "" {
+			return fmt.Errorf("model version '%s' is not supported by the broker", c.modelVersion)
+		}
+		return nil
+	}
+
+	brokerModelVersion, err := c.getBrokerModelVersion(latestModelVersion)
+	if err != nil {
+		return err
+	}
+	modelVersion, err := selectModelVersion(brokerModelVersion)
+	if err != nil {
+		return err
+	}
+	if modelVersion != brokerModelVersion {
+		version, err := c.getBrokerModelVersion(modelVersion)
+		if err != nil || version == "" {
+			return fmt.Errorf("unable to find model version supported by both the provider and the broker with model version '%s'", brokerModelVersion)
+		}
+	}
+	log.Printf("[INFO] Qpid: using model version %s for broker model version %s", modelVersion, brokerModelVersion)
+	c.setModelVersion(modelVersion)
+	return nil
+}
+
+// ModelVersion returns model version used in requests
+func (c *Client) ModelVersion() string {
+	return c.modelVersion
+}
+
+func (c *Client) setModelVersion(modelVersion string) {
+	c.modelVersion = modelVersion
+}
+
+// getBrokerModelVersion returns model version reported by the broker via API of given model version
+func (c *Client) getBrokerModelVersion(modelVersion string) (string, error) {
+	v := url.Values{}
+	v.Set("depth", "0")
+	attributes, err := c.restClient.GetAsMap("/"+apiPath(modelVersion)+"/broker", v)
+	if err != nil {
+		return "", err
+	}
+	return brokerModelVersion(attributes), nil
+}
+
+// brokerModelVersion returns model version from given broker attributes, empty string if the version is not set
+func brokerModelVersion(attributes *map[string]interface{}) string {
+	version, ok := (*attributes)["modelVersion"]
+	if !ok {
+		return ""
+	}
+	return "v" + strings.TrimPrefix(fmt.Sprintf("%v", version), "v")
+}
+
+// selectModelVersion returns the newest model version supported by the provider not exceeding given broker model version
+func selectModelVersion(brokerModelVersion string) (string, error) {
+	brokerMajor, brokerMinor, err := parseModelVersion(brokerModelVersion)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(supportedModelVersions) - 1; i >= 0; i-- {
+		major, minor, _ := parseModelVersion(supportedModelVersions[i])
+		if major < brokerMajor || (major == brokerMajor && minor <= brokerMinor) {
+			return supportedModelVersions[i], nil
+		}
+	}
+	return "", fmt.Errorf("broker model version '%s' is not supported by the provider, supported versions: %v", brokerModelVersion, supportedModelVersions)
+}
+
+// commonModelVersions returns model versions supported by the provider not exceeding given broker model version
+func commonModelVersions(brokerModelVersion string) ([]string, error) {
+	brokerMajor, brokerMinor, err := parseModelVersion(brokerModelVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(supportedModelVersions))
+	for _, version := range supportedModelVersions {
+		major, minor, _ := parseModelVersion(version)
+		if major < brokerMajor || (major == brokerMajor && minor <= brokerMinor) {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+func parseModelVersion(modelVersion string) (int, int, error) {
+	parts := strings.SplitN(strings.TrimPrefix(modelVersion, "v"), ".", 2)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid model version '%s'", modelVersion)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid model version '%s'", modelVersion)
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,135 @@
+package qpid
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryLeeway is subtracted from the token lifetime so that the token is refreshed before it expires
+const tokenExpiryLeeway = 30 * time.Second
+
+// OAuth2ClientCredentials obtains access tokens from the authorization server using
+// OAuth2 client credentials grant and sets them as bearer tokens on the requests.
+// The token is cached and requested again when it expires.
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	httpClient *http.Client
+	mutex      sync.Mutex
+	token      string
+	expiry     time.Time
+}
+
+// NewOAuth2ClientCredentials constructs OAuth2ClientCredentials requesting tokens via given transport
+func NewOAuth2ClientCredentials(tokenURL string, clientID string, clientSecret string, scopes []string, transport http.RoundTripper) *Credentials {
+	var me Credentials = &OAuth2ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		httpClient:   &http.Client{Transport: transport},
+	}
+
+	return &me
+}
+
+// Set bearer token authorization header on http request, requesting a new token if required
+func (c *OAuth2ClientCredentials) Set(request *http.Request) error {
+	token, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate discards the cached token, thus, a new token is requested for the next request
+func (c *OAuth2ClientCredentials) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = ""
+}
+
+// String ...
+func (c *OAuth2ClientCredentials) String() string {
+	return "OAuth2ClientCredentials [token url: " + c.TokenURL + ", client id: " + c.ClientID + "]"
+}
+
+func (c *OAuth2ClientCredentials) accessToken() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" && (c.expiry.IsZero() || time.Now().Before(c.expiry)) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.requestToken()
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+	c.expiry = time.Time{}
+	if expiresIn > 0 {
+		c.expiry = time.Now().Add(time.Duration(expiresIn)*time.Second - tokenExpiryLeeway)
+	}
+	return c.token, nil
+}
+
+func (c *OAuth2ClientCredentials) requestToken() (string, int64, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+
+	log.Printf("[DEBUG] Qpid: requesting OAuth2 access token from %s for client %s", c.TokenURL, c.ClientID)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		if err == nil && result.Error != "" {
+			return "", 0, fmt.Errorf("error obtaining OAuth2 access token from '%s': %s %s", c.TokenURL, result.Error, result.ErrorDescription)
+		}
+		return "", 0, fmt.Errorf("error obtaining OAuth2 access token from '%s': %s", c.TokenURL, resp.Status)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to decode OAuth2 token response from '%s': %v", c.TokenURL, err)
+	}
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("OAuth2 token response from '%s' does not contain access token", c.TokenURL)
+	}
+	if result.TokenType != "" && !strings.EqualFold(result.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("unsupported OAuth2 token type '%s'", result.TokenType)
+	}
+
+	return result.AccessToken, result.ExpiresIn, nil
+}
@@ -0,0 +1,132 @@
+package qpid
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthorizationServer issues numbered access tokens with OAuth2 client credentials grant
+type fakeAuthorizationServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	issued    int
+	scope     string
+	expiresIn int
+}
+
+func newFakeAuthorizationServer(clientID string, clientSecret string, expiresIn int) *fakeAuthorizationServer {
+	server := &fakeAuthorizationServer{expiresIn: expiresIn}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.Method != http.MethodPost || r.FormValue("grant_type") != "client_credentials" || id != clientID || secret != clientSecret {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "client authentication failed"}`))
+			return
+		}
+
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		server.issued++
+		server.scope = r.FormValue("scope")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, server.issued, server.expiresIn)
+	}))
+	return server
+}
+
+func (s *fakeAuthorizationServer) tokens() (int, string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.issued, s.scope
+}
+
+// newBearerTokenServer returns a server accepting only the last token issued by the authorization server
+func newBearerTokenServer(authorizationServer *fakeAuthorizationServer) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued, _ := authorizationServer.tokens()
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "broker"}`))
+	}))
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	authorizationServer := newFakeAuthorizationServer("client", "secret", 3600)
+	defer authorizationServer.Close()
+	server := newBearerTokenServer(authorizationServer)
+	defer server.Close()
+
+	credentials := NewOAuth2ClientCredentials(authorizationServer.URL, "client", "secret", []string{"read", "write"}, http.DefaultTransport)
+	client, err := NewSimpleRestClient(server.URL, credentials, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err = client.GetAsMap("broker", url.Values{})
+		if err != nil {
+			t.Fatalf("unable to get broker with access token: %v", err)
+		}
+	}
+	if issued, scope := authorizationServer.tokens(); issued != 1 || scope != "read write" {
+		t.Fatalf("unexpected number of issued tokens %d with scope '%s', expected token to be reused", issued, scope)
+	}
+
+	// expired token is refreshed before sending the request
+	oauth2 := (*credentials).(*OAuth2ClientCredentials)
+	oauth2.mutex.Lock()
+	oauth2.expiry = time.Now().Add(-time.Second)
+	oauth2.mutex.Unlock()
+	_, err = client.GetAsMap("broker", url.Values{})
+	if issued, _ := authorizationServer.tokens(); err != nil || issued != 2 {
+		t.Fatalf("expired token is not refreshed, issued tokens %d: %v", issued, err)
+	}
+
+	// token rejected by the broker is refreshed and the request is resent
+	oauth2.mutex.Lock()
+	oauth2.token = "revoked"
+	oauth2.mutex.Unlock()
+	_, err = client.GetAsMap("broker", url.Values{})
+	if issued, _ := authorizationServer.tokens(); err != nil || issued != 3 {
+		t.Fatalf("rejected token is not refreshed, issued tokens %d: %v", issued, err)
+	}
+}
+
+func TestOAuth2ClientCredentialsTokenLifetime(t *testing.T) {
+	// token expiring within the leeway is requested again for each request
+	authorizationServer := newFakeAuthorizationServer("client", "secret", int(tokenExpiryLeeway/time.Second)-1)
+	defer authorizationServer.Close()
+
+	credentials := NewOAuth2ClientCredentials(authorizationServer.URL, "client", "secret", nil, http.DefaultTransport)
+	for i := 1; i <= 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/broker", nil)
+		err := (*credentials).Set(req)
+		if err != nil || req.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", i) {
+			t.Fatalf("unexpected authorization header '%s': %v", req.Header.Get("Authorization"), err)
+		}
+	}
+	if _, scope := authorizationServer.tokens(); scope != "" {
+		t.Fatalf("unexpected scope '%s'", scope)
+	}
+}
+
+func TestOAuth2ClientCredentialsError(t *testing.T) {
+	authorizationServer := newFakeAuthorizationServer("client", "secret", 3600)
+	defer authorizationServer.Close()
+
+	credentials := NewOAuth2ClientCredentials(authorizationServer.URL, "client", "wrong", nil, http.DefaultTransport)
+	err := (*credentials).Set(httptest.NewRequest(http.MethodGet, "/broker", nil))
+	if err == nil || !strings.Contains(err.Error(), "invalid_client client authentication failed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package qpid
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MessageSelection selects the messages for queue operations.
+// Messages can be selected by ids or by JMS selector, zero limit selects all matching messages.
+type MessageSelection struct {
+	MessageIDs []int64
+	Selector   string
+	Limit      int
+}
+
+// nonModifyingOperations are operations which do not change the broker state
+var nonModifyingOperations = map[string]struct{}{
+	"getStatistics": {},
+	"extractConfig": {},
+}
+
+// InvokeOperation invokes the operation of the object with given category and path passing given parameters.
+// The operation result decoded from JSON is returned, nil is returned for operations without result.
+// Operations changing the broker state are sent like changes of the object and invalidate the read cache.
+func (c *Client) InvokeOperation(category string, path []string, operation string, parameters *map[string]interface{}) (interface{}, error) {
+	if parameters == nil {
+		parameters = &map[string]interface{}{}
+	}
+
+	operationPath := c.objectPath(category, path) + "/" + operation
+	var resp *http.Response
+	var err error
+	if _, nonModifying := nonModifyingOperations[operation]; nonModifying {
+		resp, err = c.restClient.Post(operationPath, parameters)
+	} else {
+		defer c.invalidateCache(category, path)
+		resp, err = c.sendChange(category, path, func(endpoint int) (*http.Response, error) {
+			return c.restClient.PostToEndpoint(endpoint, operationPath, parameters)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertHttpResponseToOperationResult(resp)
+}
+
+// ClearQueue deletes all messages from the queue and returns the number of deleted messages
+func (c *Client) ClearQueue(node string, host string, name string) (int64, error) {
+	result, err := c.InvokeOperation("queue", []string{node, host, name}, "clearQueue", nil)
+	if err != nil {
+		return 0, err
+	}
+	return toInt64(result)
+}
+
+// MoveMessages moves selected messages from the queue to destination queue and returns ids of moved messages
+func (c *Client) MoveMessages(node string, host string, name string, destination string, selection *MessageSelection) ([]int64, error) {
+	return c.transferMessages("moveMessages", node, host, name, destination, selection)
+}
+
+// CopyMessages copies selected messages from the queue to destination queue and returns ids of copied messages
+func (c *Client) CopyMessages(node string, host string, name string, destination string, selection *MessageSelection) ([]int64, error) {
+	return c.transferMessages("copyMessages", node, host, name, destination, selection)
+}
+
+func (c *Client) transferMessages(operation string, node string, host string, name string, destination string, selection *MessageSelection) ([]int64, error) {
+	parameters := map[string]interface{}{"destination": destination}
+	if selection != nil {
+		if len(selection.MessageIDs) > 0 {
+			parameters["messageIds"] = selection.MessageIDs
+		}
+		if selection.Selector != "" {
+			parameters["selector"] = selection.Selector
+		}
+		if selection.Limit > 0 {
+			parameters["limit"] = selection.Limit
+		}
+	}
+
+	result, err := c.InvokeOperation("queue", []string{node, host, name}, operation, &parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, ok := result.([]interface{})
+	if !ok && result != nil {
+		return nil, fmt.Errorf("unexpected result of %s: %v", operation, result)
+	}
+	moved := make([]int64, len(ids))
+	for i, id := range ids {
+		moved[i], err = toInt64(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return moved, nil
+}
+
+// GetQueueStatistics returns given statistics of the queue, all statistics are returned if none is specified
+func (c *Client) GetQueueStatistics(node string, host string, name string, statistics ...string) (*map[string]interface{}, error) {
+	return c.getStatistics("queue", []string{node, host, name}, statistics)
+}
+
+// ResetQueueStatistics resets the statistics of the queue
+func (c *Client) ResetQueueStatistics(node string, host string, name string) error {
+	_, err := c.InvokeOperation("queue", []string{node, host, name}, "resetStatistics", nil)
+	return err
+}
+
+// GetVirtualHostStatistics returns given statistics of the virtual host, all statistics are returned if none is specified
+func (c *Client) GetVirtualHostStatistics(node string, host string, statistics ...string) (*map[string]interface{}, error) {
+	return c.getStatistics("virtualhost", []string{node, host}, statistics)
+}
+
+// ResetVirtualHostStatistics resets the statistics of the virtual host and its children
+func (c *Client) ResetVirtualHostStatistics(node string, host string) error {
+	_, err := c.InvokeOperation("virtualhost", []string{node, host}, "resetStatistics", nil)
+	return err
+}
+
+// GetBrokerStatistics returns given statistics of the broker, all statistics are returned if none is specified
+func (c *Client) GetBrokerStatistics(statistics ...string) (*map[string]interface{}, error) {
+	return c.getStatistics("broker", nil, statistics)
+}
+
+// ResetBrokerStatistics resets the statistics of the broker and its children
+func (c *Client) ResetBrokerStatistics() error {
+	_, err := c.InvokeOperation("broker", nil, "resetStatistics", nil)
+	return err
+}
+
+func (c *Client) getStatistics(category string, path []string, statistics []string) (*map[string]interface{}, error) {
+	parameters := map[string]interface{}{}
+	if len(statistics) > 0 {
+		parameters["statistics"] = statistics
+	}
+
+	result, err := c.InvokeOperation(category, path, "getStatistics", &parameters)
+	if err != nil {
+		return nil, err
+	}
+	return toOperationResultMap("getStatistics", result)
+}
+
+// ExtractVirtualHostConfig returns the configuration of the virtual host and its children
+func (c *Client) ExtractVirtualHostConfig(node string, host string, includeSecureAttributes bool) (*map[string]interface{}, error) {
+	parameters := map[string]interface{}{"includeSecureAttributes": includeSecureAttributes}
+	result, err := c.InvokeOperation("virtualhost", []string{node, host}, "extractConfig", &parameters)
+	if err != nil {
+		return nil, err
+	}
+	return toOperationResultMap("extractConfig", result)
+}
+
+// ExtractBrokerConfig returns the configuration of the broker and its children
+func (c *Client) ExtractBrokerConfig(includeSecureAttributes bool) (*map[string]interface{}, error) {
+	parameters := map[string]interface{}{"includeSecureAttributes": includeSecureAttributes}
+	result, err := c.InvokeOperation("broker", nil, "extractConfig", &parameters)
+	if err != nil {
+		return nil, err
+	}
+	return toOperationResultMap("extractConfig", result)
+}
+
+func convertHttpResponseToOperationResult(res *http.Response) (interface{}, error) {
+	defer closeResponseBody(res)
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, newQpidAPIError(res)
+	}
+
+	var result interface{}
+	err := newJSONDecoder(res.Body).Decode(&result)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode operation result: %v", err)
+	}
+	return result, nil
+}
+
+func toOperationResultMap(operation string, result interface{}) (*map[string]interface{}, error) {
+	m, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected result of %s: %v", operation, result)
+	}
+	return &m, nil
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), nil
+	case json.Number:
+		return v.Int64()
+	default:
+		return 0, fmt.Errorf("unexpected numeric value: %v", value)
+	}
+}
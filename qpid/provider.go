@@ -4,10 +4,17 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"time"
 )
 
 // Provider ...
@@ -15,9 +22,10 @@ func Provider() terraform.ResourceProvider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"endpoint": {
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("QPID_ENDPOINT", nil),
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Endpoint of the broker. When neither endpoint nor endpoints are set, QPID_ENDPOINT environment variable is used.",
+				ConflictsWith: []string{"endpoints"},
 				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
 					value := v.(string)
 					if value == "" {
@@ -28,9 +36,20 @@ func Provider() terraform.ResourceProvider {
 				},
 			},
 
+			"endpoints": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Description:   "Endpoints of the brokers from the same replication group. Requests fail over to the next endpoint when the active one is unreachable, and changes of virtual host objects are sent to the master.",
+				ConflictsWith: []string{"endpoint"},
+				MinItems:      1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
 			"username": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("QPID_USERNAME", nil),
 				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
 					value := v.(string)
@@ -44,7 +63,7 @@ func Provider() terraform.ResourceProvider {
 
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("QPID_PASSWORD", nil),
 				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
@@ -57,9 +76,62 @@ func Provider() terraform.ResourceProvider {
 				},
 			},
 
+			"sasl_mechanism": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SASL mechanism used to log in with username and password instead of basic authentication",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_SASL_MECHANISM", nil),
+				ValidateFunc: validation.StringInSlice([]string{
+					"SCRAM-SHA-256",
+					"SCRAM-SHA-1",
+				}, false),
+			},
+
+			"access_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "Bearer token used to authenticate instead of username and password",
+				DefaultFunc:   schema.EnvDefaultFunc("QPID_ACCESS_TOKEN", nil),
+				ConflictsWith: []string{"oauth2"},
+			},
+
+			"oauth2": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				Description:   "OAuth2 client credentials used to obtain bearer tokens instead of authenticating with username and password",
+				ConflictsWith: []string{"access_token"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"token_url": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"client_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"client_secret": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"scopes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+
 			"model_version": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Description: "Version of broker management model, for example, v7.1. When not set, the newest version supported by both the broker and the provider is used.",
 				DefaultFunc: schema.EnvDefaultFunc("QPID_MODEL_VERSION", nil),
 				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
 					value := v.(string)
@@ -82,6 +154,192 @@ func Provider() terraform.ResourceProvider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("QPID_CERTIFICATE", ""),
 			},
+
+			"client_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded client certificate used for TLS client authentication",
+				DefaultFunc:   schema.EnvDefaultFunc("QPID_CLIENT_CERTIFICATE", nil),
+				ConflictsWith: []string{"client_pkcs12_file"},
+			},
+
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PEM encoded private key of the client certificate",
+				DefaultFunc:   schema.EnvDefaultFunc("QPID_CLIENT_KEY", nil),
+				ConflictsWith: []string{"client_pkcs12_file"},
+			},
+
+			"client_pkcs12_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "PKCS#12 bundle with client certificate and private key used for TLS client authentication",
+				DefaultFunc:   schema.EnvDefaultFunc("QPID_CLIENT_PKCS12", nil),
+				ConflictsWith: []string{"client_certificate_file", "client_key_file"},
+			},
+
+			"client_pkcs12_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the PKCS#12 bundle",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_CLIENT_PKCS12_PASSWORD", ""),
+			},
+
+			"retry_max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of attempts for idempotent requests failing due to transport errors or broker unavailability",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_RETRY_MAX_ATTEMPTS", 3),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"retry_base_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Delay before the first retry, doubled on every subsequent retry",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_RETRY_BASE_BACKOFF", "500ms"),
+				ValidateFunc: validateDuration,
+			},
+
+			"retry_max_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Maximum delay between retries",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_RETRY_MAX_BACKOFF", "10s"),
+				ValidateFunc: validateDuration,
+			},
+
+			"retry_jitter": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Randomize delays between retries",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_RETRY_JITTER", true),
+			},
+
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Time limit for requests to the broker, 0 means no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_REQUEST_TIMEOUT", "60s"),
+				ValidateFunc: validateDuration,
+			},
+
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "URL of HTTP(S) proxy to connect to the broker through",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_PROXY_URL", ""),
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					value := v.(string)
+					if _, err := url.Parse(value); err != nil {
+						errors = append(errors, fmt.Errorf("invalid proxy url '%s': %v", value, err))
+					}
+					return
+				},
+			},
+
+			"proxy_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("QPID_PROXY_USERNAME", ""),
+			},
+
+			"proxy_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("QPID_PROXY_PASSWORD", ""),
+			},
+
+			"headers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Additional headers sent with every request, for example, correlation id or headers required by API gateway",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				// the transport decompresses responses transparently only when it sets Accept-Encoding itself
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					for name := range v.(map[string]interface{}) {
+						if http.CanonicalHeaderKey(name) == "Accept-Encoding" {
+							errors = append(errors, fmt.Errorf("header '%s' cannot be set in %s, use compression instead", name, k))
+						}
+					}
+					return
+				},
+			},
+
+			"max_idle_connections": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of idle keep-alive connections kept in the pool",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_MAX_IDLE_CONNECTIONS", 100),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"max_idle_connections_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of idle keep-alive connections kept in the pool per broker endpoint",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_MAX_IDLE_CONNECTIONS_PER_HOST", 10),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"idle_connection_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Time after which an idle keep-alive connection is closed",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_IDLE_CONNECTION_TIMEOUT", "90s"),
+				ValidateFunc: validateDuration,
+			},
+
+			"max_connections_per_host": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of connections (and thus in-flight requests) per broker endpoint, 0 means no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_MAX_CONNECTIONS_PER_HOST", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"tls_session_cache_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of TLS sessions cached for resumption, 0 disables TLS session resumption",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_TLS_SESSION_CACHE_SIZE", 64),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"compression": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Request gzip compressed responses from the broker",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_COMPRESSION", true),
+			},
+
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of requests sent to the broker concurrently, 0 means no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "Maximum number of requests sent to the broker per second, 0 means no limit",
+				DefaultFunc:  schema.EnvDefaultFunc("QPID_REQUESTS_PER_SECOND", 0.0),
+				ValidateFunc: validation.FloatBetween(0, math.MaxFloat64),
+			},
+
+			"read_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Read queues and exchanges of a virtual host with a single request and reuse them until the virtual host is changed",
+				DefaultFunc: schema.EnvDefaultFunc("QPID_READ_CACHE", true),
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -104,18 +362,141 @@ func Provider() terraform.ResourceProvider {
 			"qpid_broker_logger_rule":      resourceBrokerLoggerRule(),
 		},
 
+		DataSourcesMap: map[string]*schema.Resource{
+			"qpid_virtual_host_node": dataSourceVirtualHostNode(),
+			"qpid_virtual_host":      dataSourceVirtualHost(),
+			"qpid_queue":             dataSourceQueue(),
+			"qpid_exchange":          dataSourceExchange(),
+			"qpid_queues":            dataSourceQueues(),
+			"qpid_exchanges":         dataSourceExchanges(),
+			"qpid_bindings":          dataSourceBindings(),
+			"qpid_broker":            dataSourceBroker(),
+			"qpid_queue_statistics":  dataSourceQueueStatistics(),
+		},
+
 		ConfigureFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
-	var username = d.Get("username").(string)
-	var password = d.Get("password").(string)
-	var endpoint = d.Get("endpoint").(string)
 	var modelVersion = d.Get("model_version").(string)
+
+	retryPolicy, err := toRetryPolicy(d)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransport(d, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := toCredentials(d, transport, len(tlsConfig.Certificates) > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := toEndpoints(d)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClientForEndpoints(endpoints, credentials, modelVersion, transport)
+	if err != nil {
+		return nil, err
+	}
+	client.SetRetryPolicy(*retryPolicy)
+
+	timeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, err
+	}
+	client.SetTimeout(timeout)
+	client.SetRequestLimits(d.Get("max_concurrent_requests").(int), d.Get("requests_per_second").(float64))
+	client.SetReadCacheEnabled(d.Get("read_cache").(bool))
+
+	err = client.ResolveModelVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.DiscoverCapabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func toEndpoints(d *schema.ResourceData) ([]string, error) {
+	if v, ok := d.GetOk("endpoints"); ok {
+		items := v.([]interface{})
+		endpoints := *convertToArrayOfStrings(&items)
+		for _, endpoint := range endpoints {
+			if endpoint == "" {
+				return nil, fmt.Errorf("endpoints must not contain empty strings")
+			}
+		}
+		return endpoints, nil
+	}
+
+	if endpoint, ok := d.GetOk("endpoint"); ok {
+		return []string{endpoint.(string)}, nil
+	}
+
+	// the environment variable is not a default of endpoint, otherwise, it would conflict with endpoints
+	if endpoint := os.Getenv("QPID_ENDPOINT"); endpoint != "" {
+		return []string{endpoint}, nil
+	}
+
+	return nil, fmt.Errorf("either endpoint or endpoints must be configured")
+}
+
+func toCredentials(d *schema.ResourceData, transport http.RoundTripper, clientCertificateSet bool) (*Credentials, error) {
+	if accessToken, ok := d.GetOk("access_token"); ok {
+		return NewBearerTokenCredentials(accessToken.(string)), nil
+	}
+
+	if v, ok := d.GetOk("oauth2"); ok {
+		oauth2 := v.([]interface{})[0].(map[string]interface{})
+		scopes := oauth2["scopes"].([]interface{})
+		return NewOAuth2ClientCredentials(
+			oauth2["token_url"].(string),
+			oauth2["client_id"].(string),
+			oauth2["client_secret"].(string),
+			*convertToArrayOfStrings(&scopes),
+			transport), nil
+	}
+
+	username, usernameSet := d.GetOk("username")
+	password, passwordSet := d.GetOk("password")
+	if usernameSet && passwordSet {
+		if mechanism := d.Get("sasl_mechanism").(string); mechanism != "" {
+			return NewScramCredentials(username.(string), password.(string), mechanism, transport)
+		}
+		return NewBasicAuthCredentials(username.(string), password.(string)), nil
+	}
+
+	// client is authenticated with TLS client certificate
+	if clientCertificateSet {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("either username and password, access_token, oauth2 or client certificate must be configured")
+}
+
+func newTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
 	var skipCertVerification = d.Get("skip_cert_verification").(bool)
 	var certificateFile = d.Get("certificate_file").(string)
+	var clientCertificateFile = d.Get("client_certificate_file").(string)
+	var clientKeyFile = d.Get("client_key_file").(string)
+	var clientPKCS12File = d.Get("client_pkcs12_file").(string)
 
 	tlsConfig := &tls.Config{}
 	tlsConfig.InsecureSkipVerify = skipCertVerification
@@ -130,11 +511,104 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		tlsConfig.RootCAs = certPool
 	}
 
-	transport := &http.Transport{TLSClientConfig: tlsConfig}
-	client, err := NewClient(endpoint, username, password, modelVersion, transport)
+	if clientCertificateFile != "" || clientKeyFile != "" {
+		if clientCertificateFile == "" || clientKeyFile == "" {
+			return nil, fmt.Errorf("both client_certificate_file and client_key_file must be set")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(clientCertificateFile, clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate '%s' with key '%s': %v", clientCertificateFile, clientKeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if clientPKCS12File != "" {
+		certificate, err := loadPKCS12Certificate(clientPKCS12File, d.Get("client_pkcs12_password").(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate from PKCS#12 bundle '%s': %v", clientPKCS12File, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{*certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+func newTransport(d *schema.ResourceData, tlsConfig *tls.Config) (http.RoundTripper, error) {
+	idleConnectionTimeout, err := time.ParseDuration(d.Get("idle_connection_timeout").(string))
 	if err != nil {
 		return nil, err
 	}
 
-	return client, nil
+	timeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsSessionCacheSize := d.Get("tls_session_cache_size").(int); tlsSessionCacheSize > 0 {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(tlsSessionCacheSize)
+	}
+
+	// unless compression is disabled, the transport requests gzip encoding and decompresses the responses transparently
+	transport := &http.Transport{
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConns:          d.Get("max_idle_connections").(int),
+		MaxIdleConnsPerHost:   d.Get("max_idle_connections_per_host").(int),
+		MaxConnsPerHost:       d.Get("max_connections_per_host").(int),
+		IdleConnTimeout:       idleConnectionTimeout,
+		DialContext:           (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		DisableCompression:    !d.Get("compression").(bool),
+	}
+
+	if proxyURL := d.Get("proxy_url").(string); proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		if proxyUsername := d.Get("proxy_username").(string); proxyUsername != "" {
+			u.User = url.UserPassword(proxyUsername, d.Get("proxy_password").(string))
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if logging.LogLevel() == "TRACE" {
+		roundTripper = NewLoggingTransport(transport)
+	}
+
+	headers := d.Get("headers").(map[string]interface{})
+	if len(headers) > 0 {
+		roundTripper = NewHeaderTransport(roundTripper, *convertToMapOfStrings(&headers))
+	}
+	return transportWrapper(roundTripper), nil
+}
+
+// transportWrapper wraps the transport of the provider, tests replace it in order to record or replay broker interactions
+var transportWrapper = func(transport http.RoundTripper) http.RoundTripper {
+	return transport
+}
+
+func toRetryPolicy(d *schema.ResourceData) (*RetryPolicy, error) {
+	baseBackoff, err := time.ParseDuration(d.Get("retry_base_backoff").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackoff, err := time.ParseDuration(d.Get("retry_max_backoff").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	if maxBackoff < baseBackoff {
+		return nil, fmt.Errorf("retry_max_backoff '%v' must not be less than retry_base_backoff '%v'", maxBackoff, baseBackoff)
+	}
+
+	return &RetryPolicy{
+		MaxAttempts: d.Get("retry_max_attempts").(int),
+		BaseBackoff: baseBackoff,
+		MaxBackoff:  maxBackoff,
+		Jitter:      d.Get("retry_jitter").(bool),
+	}, nil
 }
@@ -1,8 +1,18 @@
 package qpid
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -10,14 +20,18 @@ import (
 
 // Running instance Qpid broker is required to run the acceptance tests
 //
-// The environment variables QPID_ENDPOINT, QPID_USERNAME, QPID_PASSWORD and QPID_MODEL_VERSION
+// The environment variables QPID_ENDPOINT, QPID_USERNAME and QPID_PASSWORD
 // needs to be set in order to run the tests.
 //
 // Optionally, QPID_CERTIFICATE and QPID_SKIP_CERT_VERIFICATION can be set for a self-signed certificate
 //
+// Optionally, QPID_MODEL_VERSION can be set to run the tests against specific model version
+//
 // The tests can be run like below
 //    make testacc
 //
+// The broker interactions can be recorded and replayed without broker as described in http_fixtures_test.go
+//
 // Alternatively, the acceptance tests can be executed as below
 //    go test $(go list ./... |grep -v 'vendor') -v  -timeout 120m
 
@@ -31,16 +45,301 @@ func init() {
 	}
 }
 
+func TestMain(m *testing.M) {
+	var broker *fakeBroker
+	if os.Getenv("QPID_FAKE_BROKER") != "" {
+		broker = startFakeBrokerForAcceptanceTests()
+	}
+	setUpHTTPFixturesEnvironment()
+
+	code := m.Run()
+	if broker != nil {
+		broker.Close()
+	}
+	os.Exit(code)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("error: %s", err)
 	}
 }
 
+func TestProviderDefaultConfiguration(t *testing.T) {
+	raw := map[string]interface{}{"endpoint": "http://localhost:8080", "username": "admin", "password": "admin"}
+	_, errs := Provider().Validate(terraform.NewResourceConfigRaw(raw))
+	if len(errs) > 0 {
+		t.Fatalf("configuration with endpoint and credentials is invalid: %v", errs)
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	headers := map[string]bool{"X-Correlation-Id": true, "Accept-Encoding": false, "accept-encoding": false}
+	for name, valid := range headers {
+		raw := map[string]interface{}{
+			"endpoint": "http://localhost:8080",
+			"username": "admin",
+			"password": "admin",
+			"headers":  map[string]interface{}{name: "identity"},
+		}
+		_, errs := Provider().Validate(terraform.NewResourceConfigRaw(raw))
+		if (len(errs) == 0) != valid {
+			t.Errorf("unexpected validation result of header '%s': %v", name, errs)
+		}
+	}
+}
+
+func TestProviderEndpoints(t *testing.T) {
+	endpoint, set := os.LookupEnv("QPID_ENDPOINT")
+	defer func() {
+		if set {
+			os.Setenv("QPID_ENDPOINT", endpoint)
+		} else {
+			os.Unsetenv("QPID_ENDPOINT")
+		}
+	}()
+	os.Setenv("QPID_ENDPOINT", "http://environment:8080")
+
+	raw := map[string]interface{}{"endpoints": []interface{}{"http://first:8080", "http://second:8080"}, "username": "admin", "password": "admin"}
+	_, errs := Provider().Validate(terraform.NewResourceConfigRaw(raw))
+	if len(errs) > 0 {
+		t.Fatalf("configuration with endpoints is invalid when QPID_ENDPOINT is set: %v", errs)
+	}
+
+	configurations := []struct {
+		raw       map[string]interface{}
+		endpoints string
+	}{
+		{raw: raw, endpoints: "http://first:8080 http://second:8080"},
+		{raw: map[string]interface{}{"endpoint": "http://configured:8080"}, endpoints: "http://configured:8080"},
+		{raw: map[string]interface{}{}, endpoints: "http://environment:8080"},
+	}
+	for _, configuration := range configurations {
+		endpoints, err := toEndpoints(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, configuration.raw))
+		if err != nil || strings.Join(endpoints, " ") != configuration.endpoints {
+			t.Errorf("unexpected endpoints %v, expected %s: %v", endpoints, configuration.endpoints, err)
+		}
+	}
+
+	os.Unsetenv("QPID_ENDPOINT")
+	_, err := toEndpoints(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{}))
+	if err == nil {
+		t.Errorf("endpoints are resolved without configuration")
+	}
+}
+
+// newClientCertificateServer returns a TLS server requiring client certificate issued by the test CA.
+// The client certificate in testdata/tls is issued by the test CA, its PKCS#12 bundle is protected with password "secret".
+func newClientCertificateServer(t *testing.T) *httptest.Server {
+	ca, err := ioutil.ReadFile("testdata/tls/ca.pem")
+	if err != nil {
+		t.Fatalf("unable to read CA certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(ca)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	return server
+}
+
+func TestProviderClientCertificate(t *testing.T) {
+	server := newClientCertificateServer(t)
+	defer server.Close()
+
+	configurations := map[string]map[string]interface{}{
+		"PEM": {
+			"client_certificate_file": "testdata/tls/client.pem",
+			"client_key_file":         "testdata/tls/client-key.pem",
+		},
+		"PKCS#12": {
+			"client_pkcs12_file":     "testdata/tls/client.p12",
+			"client_pkcs12_password": "secret",
+		},
+		"none": {},
+	}
+	for name, raw := range configurations {
+		raw["skip_cert_verification"] = true
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+		tlsConfig, err := newTLSConfig(d)
+		if err != nil {
+			t.Fatalf("unable to create TLS configuration with %s client certificate: %v", name, err)
+		}
+		transport, err := newTransport(d, tlsConfig)
+		if err != nil {
+			t.Fatalf("unable to create transport with %s client certificate: %v", name, err)
+		}
+		client, err := NewSimpleRestClient(server.URL, nil, transport)
+		if err != nil {
+			t.Fatalf("unable to create client: %v", err)
+		}
+
+		attributes, err := client.GetAsMap("broker", url.Values{})
+		if name == "none" {
+			if err == nil {
+				t.Errorf("request without client certificate succeeded")
+			}
+		} else if err != nil || (*attributes)["name"] != "client" {
+			t.Errorf("unexpected result of request with %s client certificate %v: %v", name, attributes, err)
+		}
+	}
+}
+
+func TestProviderClientCertificateErrors(t *testing.T) {
+	configurations := map[string]map[string]interface{}{
+		"both client_certificate_file and client_key_file must be set": {
+			"client_certificate_file": "testdata/tls/client.pem",
+		},
+		"unable to load client certificate 'testdata/tls/client.pem' with key 'testdata/tls/ca.pem'": {
+			"client_certificate_file": "testdata/tls/client.pem",
+			"client_key_file":         "testdata/tls/ca.pem",
+		},
+		"unable to load client certificate from PKCS#12 bundle 'testdata/tls/client.p12'": {
+			"client_pkcs12_file":     "testdata/tls/client.p12",
+			"client_pkcs12_password": "wrong",
+		},
+	}
+	for expected, raw := range configurations {
+		_, err := newTLSConfig(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("unexpected error '%v', expected '%s'", err, expected)
+		}
+	}
+}
+
+func TestProviderProxyAndHeaders(t *testing.T) {
+	var proxied *http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "broker"}`))
+	}))
+	defer proxy.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"proxy_url":      proxy.URL,
+		"proxy_username": "proxy-user",
+		"proxy_password": "proxy-password",
+		"headers":        map[string]interface{}{"X-Correlation-Id": "correlation"},
+	})
+	transport, err := newTransport(d, &tls.Config{})
+	if err != nil {
+		t.Fatalf("unable to create transport: %v", err)
+	}
+	client, err := NewSimpleRestClient("http://broker.invalid:8080", nil, transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	attributes, err := client.GetAsMap("broker", url.Values{})
+	if err != nil || (*attributes)["name"] != "broker" || proxied == nil {
+		t.Fatalf("request is not sent via proxy %v: %v", attributes, err)
+	}
+	if proxied.Host != "broker.invalid:8080" || proxied.Header.Get("X-Correlation-Id") != "correlation" {
+		t.Errorf("unexpected proxied request to '%s' with headers %v", proxied.Host, proxied.Header)
+	}
+	username, password, _ := (&http.Request{Header: http.Header{"Authorization": proxied.Header["Proxy-Authorization"]}}).BasicAuth()
+	if username != "proxy-user" || password != "proxy-password" {
+		t.Errorf("unexpected proxy credentials '%s' and '%s'", username, password)
+	}
+}
+
+func TestProviderRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{"request_timeout": "50ms"})
+	transport, err := newTransport(d, &tls.Config{})
+	if err != nil {
+		t.Fatalf("unable to create transport: %v", err)
+	}
+	client, err := NewSimpleRestClient(server.URL, nil, transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.GetAsMap("broker", url.Values{})
+	if err == nil || time.Since(start) > 5*time.Second {
+		t.Fatalf("request to unresponsive broker did not time out after %v: %v", time.Since(start), err)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{"request_timeout": "later"})
+	if _, err = newTransport(d, &tls.Config{}); err == nil {
+		t.Fatalf("invalid request timeout is accepted")
+	}
+}
+
 func testAcceptancePreCheck(t *testing.T) {
-	for _, name := range []string{"QPID_ENDPOINT", "QPID_USERNAME", "QPID_PASSWORD", "QPID_MODEL_VERSION"} {
+	useHTTPFixtures(t)
+	for _, name := range []string{"QPID_ENDPOINT", "QPID_USERNAME", "QPID_PASSWORD"} {
 		if v := os.Getenv(name); v == "" {
-			t.Fatal("QPID_ENDPOINT, QPID_USERNAME, QPID_PASSWORD and QPID_MODEL_VERSION must be set for acceptance tests")
+			t.Fatal("QPID_ENDPOINT, QPID_USERNAME and QPID_PASSWORD must be set for acceptance tests")
 		}
 	}
 }
+
+func TestProviderConnectionPool(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"max_idle_connections":          20,
+		"max_idle_connections_per_host": 5,
+		"max_connections_per_host":      8,
+		"idle_connection_timeout":       "45s",
+		"tls_session_cache_size":        16,
+	})
+	tlsConfig := &tls.Config{}
+	roundTripper, err := newTransport(d, tlsConfig)
+	if err != nil {
+		t.Fatalf("unable to create transport: %v", err)
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", roundTripper)
+	}
+	if transport.MaxIdleConns != 20 || transport.MaxIdleConnsPerHost != 5 || transport.MaxConnsPerHost != 8 ||
+		transport.IdleConnTimeout != 45*time.Second || transport.DisableKeepAlives {
+		t.Errorf("connection pool settings are not applied: %+v", transport)
+	}
+	if transport.TLSClientConfig != tlsConfig || tlsConfig.ClientSessionCache == nil {
+		t.Errorf("TLS session cache is not configured")
+	}
+
+	var mutex sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "broker"}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mutex.Lock()
+			connections++
+			mutex.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, err := NewSimpleRestClient(server.URL, nil, transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err = client.GetAsMap("broker", url.Values{}); err != nil {
+			t.Fatalf("unable to get broker: %v", err)
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if connections != 1 {
+		t.Errorf("connection is not reused, %d connections are opened", connections)
+	}
+}
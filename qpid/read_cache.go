@@ -0,0 +1,154 @@
+package qpid
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// cachedCategories are categories of virtual host children served from the read cache
+var cachedCategories = map[string]string{
+	"queue":    "queues",
+	"exchange": "exchanges",
+}
+
+// readCache holds virtual host hierarchies fetched with a single request.
+// The hierarchy of a virtual host is fetched on first lookup of its queue or exchange
+// and discarded on any change under the virtual host. Failed fetches are not cached.
+type readCache struct {
+	mutex       sync.Mutex
+	hierarchies map[string]*cachedHierarchy
+}
+
+// cachedHierarchy holds children of virtual host, the children are nil until fetched successfully
+type cachedHierarchy struct {
+	mutex    sync.Mutex
+	children map[string]map[string]map[string]interface{}
+}
+
+func newReadCache() *readCache {
+	return &readCache{hierarchies: map[string]*cachedHierarchy{}}
+}
+
+// SetReadCacheEnabled turns on or off serving queue and exchange lookups from the virtual host hierarchies
+// fetched with a single request
+func (c *Client) SetReadCacheEnabled(enabled bool) {
+	if enabled {
+		c.cache = newReadCache()
+	} else {
+		c.cache = nil
+	}
+}
+
+// getCached returns attributes of the object from the read cache.
+// The returned flag is false if the object cannot be served from the cache.
+func (c *Client) getCached(category string, path []string, options *QueryOptions) (*map[string]interface{}, bool, error) {
+	children, cacheable := cachedCategories[category]
+	if c.cache == nil || !cacheable || len(path) != 3 || options == nil ||
+		*options != (QueryOptions{Actuals: options.Actuals}) {
+		return nil, false, nil
+	}
+
+	hierarchy, err := c.cache.hierarchy(path[0], path[1], options.Actuals).fetch(func() (map[string]map[string]map[string]interface{}, error) {
+		return c.getHierarchy(path[0], path[1], options.Actuals)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+
+	attributes, found := hierarchy[children][path[2]]
+	if !found {
+		return nil, true, &QpidAPIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("%s '%s' not found", category, path[2]),
+			Path:       configuredObjectPath(category, path),
+		}
+	}
+
+	result := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		result[k] = v
+	}
+	return &result, true, nil
+}
+
+func (c *Client) getHierarchy(node string, host string, actuals bool) (map[string]map[string]map[string]interface{}, error) {
+	log.Printf("[DEBUG] Qpid: fetching hierarchy of virtual host '%s/%s'", node, host)
+	attributes, err := c.restClient.GetAsMap(c.objectPath("virtualhost", []string{node, host}),
+		(&QueryOptions{Actuals: actuals, Depth: 1}).Values())
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]map[string]map[string]interface{}{}
+	for _, children := range cachedCategories {
+		objects := map[string]map[string]interface{}{}
+		if list, ok := (*attributes)[children].([]interface{}); ok {
+			for _, item := range list {
+				if object, ok := item.(map[string]interface{}); ok {
+					if name, ok := object["name"].(string); ok {
+						objects[name] = object
+					}
+				}
+			}
+		}
+		result[children] = objects
+	}
+	return result, nil
+}
+
+func (r *readCache) hierarchy(node string, host string, actuals bool) *cachedHierarchy {
+	key := hierarchyKey(node, host, actuals)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	hierarchy, found := r.hierarchies[key]
+	if !found {
+		hierarchy = &cachedHierarchy{}
+		r.hierarchies[key] = hierarchy
+	}
+	return hierarchy
+}
+
+// fetch returns the cached children, fetching them with given function if not fetched yet
+func (h *cachedHierarchy) fetch(get func() (map[string]map[string]map[string]interface{}, error)) (map[string]map[string]map[string]interface{}, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.children == nil {
+		children, err := get()
+		if err != nil {
+			return nil, err
+		}
+		h.children = children
+	}
+	return h.children, nil
+}
+
+// invalidateCache discards cached hierarchies affected by a change of the object with given category and path
+func (c *Client) invalidateCache(category string, path []string) {
+	if c.cache == nil {
+		return
+	}
+
+	_, virtualHostObject := virtualHostCategories[category]
+	if !virtualHostObject && category != "virtualhostnode" {
+		return
+	}
+
+	c.cache.mutex.Lock()
+	defer c.cache.mutex.Unlock()
+	if len(path) < 2 || category == "virtualhostnode" {
+		// the change can affect virtual hosts of the whole node
+		c.cache.hierarchies = map[string]*cachedHierarchy{}
+		return
+	}
+	for _, actuals := range []bool{true, false} {
+		delete(c.cache.hierarchies, hierarchyKey(path[0], path[1], actuals))
+	}
+}
+
+func hierarchyKey(node string, host string, actuals bool) string {
+	return configuredObjectPath("virtualhost", []string{node, host}) + "?actuals=" + strconv.FormatBool(actuals)
+}
@@ -0,0 +1,118 @@
+package qpid
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// hierarchyTransport counts requests fetching virtual host hierarchies and rejects them when failing
+type hierarchyTransport struct {
+	mutex    sync.Mutex
+	requests int
+	failing  bool
+}
+
+func (t *hierarchyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("depth") != "1" {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	t.mutex.Lock()
+	t.requests++
+	failing := t.failing
+	t.mutex.Unlock()
+	if failing {
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Status:     "403 Forbidden",
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("Access denied")),
+			Request:    req,
+		}, nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (t *hierarchyTransport) count() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.requests
+}
+
+func (t *hierarchyTransport) setFailing(failing bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.failing = failing
+}
+
+func TestClientReadCache(t *testing.T) {
+	broker := newFakeBroker(fakeBrokerUsername, fakeBrokerPassword)
+	defer broker.Close()
+	transport := &hierarchyTransport{}
+	client, err := NewClient(broker.URL(), fakeBrokerUsername, fakeBrokerPassword, "", transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	createFakeVirtualHost(t, client, "node", "host")
+	for _, name := range []string{"foo", "bar"} {
+		_, err = client.CreateQueue("node", "host", &map[string]interface{}{"name": name})
+		if err != nil {
+			t.Fatalf("unable to create queue '%s': %v", name, err)
+		}
+	}
+	client.SetReadCacheEnabled(true)
+
+	// lookups of the virtual host children are served from a single hierarchy request
+	for _, name := range []string{"foo", "bar", "foo"} {
+		queue, err := client.GetQueue("node", "host", name)
+		if err != nil || (*queue)["name"] != name {
+			t.Fatalf("unexpected queue %v: %v", queue, err)
+		}
+	}
+	_, err = client.GetExchange("node", "host", "amq.direct")
+	if err != nil {
+		t.Fatalf("unable to get exchange: %v", err)
+	}
+	_, err = client.GetQueue("node", "host", "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("unexpected error getting missing queue: %v", err)
+	}
+	if requests := transport.count(); requests != 1 {
+		t.Fatalf("unexpected number of hierarchy requests %d", requests)
+	}
+
+	// change under the virtual host discards the hierarchy
+	_, err = client.UpdateQueue("node", "host", "foo", &map[string]interface{}{"maximumDeliveryAttempts": 3})
+	if err != nil {
+		t.Fatalf("unable to update queue: %v", err)
+	}
+	queue, err := client.GetQueue("node", "host", "foo")
+	if err != nil || (*queue)["maximumDeliveryAttempts"] != json.Number("3") || transport.count() != 2 {
+		t.Fatalf("updated queue %v is not fetched again after %d hierarchy requests: %v", queue, transport.count(), err)
+	}
+
+	// failed fetch is not cached
+	_, err = client.DeleteQueue("node", "host", "bar")
+	if err != nil {
+		t.Fatalf("unable to delete queue: %v", err)
+	}
+	transport.setFailing(true)
+	_, err = client.GetQueue("node", "host", "foo")
+	if err == nil || !strings.Contains(err.Error(), "Access denied") {
+		t.Fatalf("unexpected error getting queue when hierarchy request fails: %v", err)
+	}
+	transport.setFailing(false)
+	queue, err = client.GetQueue("node", "host", "foo")
+	if err != nil || (*queue)["name"] != "foo" || transport.count() != 4 {
+		t.Fatalf("unexpected queue %v after failed fetch and %d hierarchy requests: %v", queue, transport.count(), err)
+	}
+	_, err = client.GetQueue("node", "host", "bar")
+	if !IsNotFound(err) || transport.count() != 4 {
+		t.Fatalf("unexpected error getting deleted queue after %d hierarchy requests: %v", transport.count(), err)
+	}
+}
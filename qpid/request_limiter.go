@@ -0,0 +1,73 @@
+package qpid
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// requestLimiter limits the number of concurrently executing requests and the rate of sending requests.
+// Requests exceeding the limits wait until they can be sent instead of failing.
+type requestLimiter struct {
+	slots    chan struct{}
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRequestLimiter creates limiter for given maximum of concurrent requests and requests per second,
+// zero values mean no limit
+func newRequestLimiter(maxConcurrentRequests int, requestsPerSecond float64) *requestLimiter {
+	limiter := &requestLimiter{}
+	if maxConcurrentRequests > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrentRequests)
+	}
+	if requestsPerSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return limiter
+}
+
+// acquire waits until the request can be sent, the returned function must be called when the request completes.
+// The slot is taken before reserving the rate interval, so that time spent waiting for a slot does not use up
+// the interval and queued requests are still spaced by it.
+func (l *requestLimiter) acquire() func() {
+	release := func() {}
+	if l.slots != nil {
+		l.slots <- struct{}{}
+		release = func() { <-l.slots }
+	}
+
+	if l.interval > 0 {
+		time.Sleep(l.reserve())
+	}
+	return release
+}
+
+// reserve returns the delay after which the next request can be sent according to the rate limit
+func (l *requestLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return delay
+}
+
+// releasingBody is a response body releasing the request slot once the body is closed,
+// thus, the slot is held while the response is being read
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -9,11 +9,12 @@ import (
 func resourceAccessControlProvider() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createAccessControlProvider,
-		Read:   readAccessControlProvider,
-		Delete: deleteAccessControlProvider,
-		Update: updateAccessControlProvider,
-		Exists: existsAccessControlProvider,
+		Create:        createAccessControlProvider,
+		Read:          readAccessControlProvider,
+		Delete:        deleteAccessControlProvider,
+		Update:        updateAccessControlProvider,
+		Exists:        existsAccessControlProvider,
+		CustomizeDiff: verifyBrokerCapabilities("accesscontrolprovider", resourceAccessControlProvider, "rule"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -192,7 +193,10 @@ func createAccessControlProvider(d *schema.ResourceData, meta interface{}) error
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -230,13 +234,13 @@ func readAccessControlProvider(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetAccessControlProvider(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
-	if len(*attributes) == 0 {
-		return nil
-	}
-
 	err = applyResourceAttributes(d, attributes, "rule")
 	if err != nil {
 		return err
@@ -271,13 +275,13 @@ func readAccessControlProvider(d *schema.ResourceData, meta interface{}) error {
 func existsAccessControlProvider(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetAccessControlProvider(name)
+	_, err := client.GetAccessControlProvider(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
@@ -9,11 +9,12 @@ import (
 func resourceAuthenticationProvider() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createAuthenticationProvider,
-		Read:   readAuthenticationProvider,
-		Delete: deleteAuthenticationProvider,
-		Update: updateAuthenticationProvider,
-		Exists: existsAuthenticationProvider,
+		Create:        createAuthenticationProvider,
+		Read:          readAuthenticationProvider,
+		Delete:        deleteAuthenticationProvider,
+		Update:        updateAuthenticationProvider,
+		Exists:        existsAuthenticationProvider,
+		CustomizeDiff: verifyBrokerCapabilities("authenticationprovider", resourceAuthenticationProvider),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -402,7 +403,10 @@ func createAuthenticationProvider(d *schema.ResourceData, meta interface{}) erro
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -422,6 +426,10 @@ func readAuthenticationProvider(d *schema.ResourceData, meta interface{}) error
 	name := d.Get("name").(string)
 	attributes, err := client.GetAuthenticationProvider(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -431,13 +439,13 @@ func readAuthenticationProvider(d *schema.ResourceData, meta interface{}) error
 func existsAuthenticationProvider(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetAuthenticationProvider(name)
+	_, err := client.GetAuthenticationProvider(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
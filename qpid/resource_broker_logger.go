@@ -9,11 +9,12 @@ import (
 func resourceBrokerLogger() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createBrokerLogger,
-		Read:   readBrokerLogger,
-		Delete: deleteBrokerLogger,
-		Update: updateBrokerLogger,
-		Exists: existsBrokerLogger,
+		Create:        createBrokerLogger,
+		Read:          readBrokerLogger,
+		Delete:        deleteBrokerLogger,
+		Update:        updateBrokerLogger,
+		Exists:        existsBrokerLogger,
+		CustomizeDiff: verifyBrokerCapabilities("brokerlogger", resourceBrokerLogger, "rule"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -352,7 +353,10 @@ func createBrokerLogger(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -390,13 +394,13 @@ func readBrokerLogger(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetBrokerLogger(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
-	if len(*attributes) == 0 {
-		return nil
-	}
-
 	err = applyResourceAttributes(d, attributes, "rule")
 	if err != nil {
 		return err
@@ -431,13 +435,13 @@ func readBrokerLogger(d *schema.ResourceData, meta interface{}) error {
 func existsBrokerLogger(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetBrokerLogger(name)
+	_, err := client.GetBrokerLogger(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
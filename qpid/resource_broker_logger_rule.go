@@ -9,11 +9,12 @@ import (
 
 func resourceBrokerLoggerRule() *schema.Resource {
 	return &schema.Resource{
-		Create: createBrokerLoggerRule,
-		Read:   readBrokerLoggerRule,
-		Delete: deleteBrokerLoggerRule,
-		Update: updateBrokerLoggerRule,
-		Exists: existsBrokerLoggerRule,
+		Create:        createBrokerLoggerRule,
+		Read:          readBrokerLoggerRule,
+		Delete:        deleteBrokerLoggerRule,
+		Update:        updateBrokerLoggerRule,
+		Exists:        existsBrokerLoggerRule,
+		CustomizeDiff: verifyBrokerCapabilities("brokerloginclusionrule", resourceBrokerLoggerRule, "broker_logger"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -126,7 +127,10 @@ func createBrokerLoggerRule(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -149,6 +153,10 @@ func readBrokerLoggerRule(d *schema.ResourceData, meta interface{}) error {
 
 	attributes, err := client.GetBrokerLoggerRule(brokerLogger, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -161,12 +169,15 @@ func existsBrokerLoggerRule(d *schema.ResourceData, meta interface{}) (bool, err
 
 	name := d.Get("name").(string)
 	brokerLogger := d.Get("broker_logger").(string)
-	attributes, err := client.GetBrokerLoggerRule(brokerLogger, name)
+	_, err := client.GetBrokerLoggerRule(brokerLogger, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteBrokerLoggerRule(d *schema.ResourceData, meta interface{}) error {
@@ -202,6 +213,6 @@ func updateBrokerLoggerRule(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 	return fmt.Errorf("error updating qpid broker logger rule '%s' on node '%s': %s, %v", name, brokerLogger, resp.Status, m)
 }
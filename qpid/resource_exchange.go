@@ -8,11 +8,12 @@ import (
 
 func resourceExchange() *schema.Resource {
 	return &schema.Resource{
-		Create: createExchange,
-		Read:   readExchange,
-		Delete: deleteExchange,
-		Update: updateExchange,
-		Exists: existsExchange,
+		Create:        createExchange,
+		Read:          readExchange,
+		Delete:        deleteExchange,
+		Update:        updateExchange,
+		Exists:        existsExchange,
+		CustomizeDiff: verifyBrokerCapabilities("exchange", resourceExchange, "virtual_host_node", "virtual_host"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -153,7 +154,10 @@ func createExchange(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -190,6 +194,10 @@ func readExchange(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetExchange(node.(string), host.(string), name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -229,13 +237,13 @@ func existsExchange(d *schema.ResourceData, meta interface{}) (bool, error) {
 		return false, fmt.Errorf("virtual_host_node and virtual_host are not set")
 	}
 	name := d.Get("name").(string)
-	attributes, err := client.GetExchange(node.(string), host.(string), name)
+	_, err := client.GetExchange(node.(string), host.(string), name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 
 	return true, nil
 }
@@ -9,11 +9,12 @@ import (
 
 func resourceGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: createGroup,
-		Read:   readGroup,
-		Delete: deleteGroup,
-		Update: updateGroup,
-		Exists: existsGroup,
+		Create:        createGroup,
+		Read:          readGroup,
+		Delete:        deleteGroup,
+		Update:        updateGroup,
+		Exists:        existsGroup,
+		CustomizeDiff: verifyBrokerCapabilities("group", resourceGroup, "group_provider"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -90,7 +91,10 @@ func createGroup(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -113,6 +117,10 @@ func readGroup(d *schema.ResourceData, meta interface{}) error {
 
 	attributes, err := client.GetGroup(groupProvider, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -125,12 +133,15 @@ func existsGroup(d *schema.ResourceData, meta interface{}) (bool, error) {
 
 	name := d.Get("name").(string)
 	groupProvider := d.Get("group_provider").(string)
-	attributes, err := client.GetGroup(groupProvider, name)
+	_, err := client.GetGroup(groupProvider, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteGroup(d *schema.ResourceData, meta interface{}) error {
@@ -9,11 +9,12 @@ import (
 
 func resourceGroupMember() *schema.Resource {
 	return &schema.Resource{
-		Create: createGroupMember,
-		Read:   readGroupMember,
-		Delete: deleteGroupMember,
-		Update: updateGroupMember,
-		Exists: existsGroupMember,
+		Create:        createGroupMember,
+		Read:          readGroupMember,
+		Delete:        deleteGroupMember,
+		Update:        updateGroupMember,
+		Exists:        existsGroupMember,
+		CustomizeDiff: verifyBrokerCapabilities("groupmember", resourceGroupMember, "group_provider", "group"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -97,7 +98,10 @@ func createGroupMember(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -120,6 +124,10 @@ func readGroupMember(d *schema.ResourceData, meta interface{}) error {
 	groupName := d.Get("group").(string)
 	attributes, err := client.GetGroupMember(groupProvider, groupName, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 	return applyResourceAttributes(d, attributes, "group_provider", "group")
@@ -132,12 +140,15 @@ func existsGroupMember(d *schema.ResourceData, meta interface{}) (bool, error) {
 	name := d.Get("name").(string)
 	groupProvider := d.Get("group_provider").(string)
 	groupName := d.Get("group").(string)
-	attributes, err := client.GetGroupMember(groupProvider, groupName, name)
+	_, err := client.GetGroupMember(groupProvider, groupName, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteGroupMember(d *schema.ResourceData, meta interface{}) error {
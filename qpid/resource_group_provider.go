@@ -9,11 +9,12 @@ import (
 func resourceGroupProvider() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createGroupProvider,
-		Read:   readGroupProvider,
-		Delete: deleteGroupProvider,
-		Update: updateGroupProvider,
-		Exists: existsGroupProvider,
+		Create:        createGroupProvider,
+		Read:          readGroupProvider,
+		Delete:        deleteGroupProvider,
+		Update:        updateGroupProvider,
+		Exists:        existsGroupProvider,
+		CustomizeDiff: verifyBrokerCapabilities("groupprovider", resourceGroupProvider),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -129,7 +130,10 @@ func createGroupProvider(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -149,6 +153,10 @@ func readGroupProvider(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetGroupProvider(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -158,13 +166,13 @@ func readGroupProvider(d *schema.ResourceData, meta interface{}) error {
 func existsGroupProvider(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetGroupProvider(name)
+	_, err := client.GetGroupProvider(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
@@ -9,11 +9,12 @@ import (
 func resourceKeyStore() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createKeyStore,
-		Read:   readKeyStore,
-		Delete: deleteKeyStore,
-		Update: updateKeyStore,
-		Exists: existsKeyStore,
+		Create:        createKeyStore,
+		Read:          readKeyStore,
+		Delete:        deleteKeyStore,
+		Update:        updateKeyStore,
+		Exists:        existsKeyStore,
+		CustomizeDiff: verifyBrokerCapabilities("keystore", resourceKeyStore),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -213,12 +214,15 @@ func createKeyStore(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 
 	return fmt.Errorf("error creating qpid key store'%s': %s, %v", name, resp.Status, m)
 }
@@ -234,6 +238,10 @@ func readKeyStore(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetKeyStore(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -243,13 +251,13 @@ func readKeyStore(d *schema.ResourceData, meta interface{}) error {
 func existsKeyStore(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetKeyStore(name)
+	_, err := client.GetKeyStore(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
@@ -289,7 +297,7 @@ func updateKeyStore(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("qpid  key store '%s' does not exist", name)
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 
 	return fmt.Errorf("error updating qpid key store '%s': %s : %v", name, resp.Status, m)
 }
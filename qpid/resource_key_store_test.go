@@ -9,6 +9,7 @@ import (
 )
 
 func TestAcceptanceKeyStore(t *testing.T) {
+	skipHTTPFixturesReplay(t, "new certificate is generated on every run")
 
 	privateKey, certificateBytes, err := generateSelfSigned("Foo Org", "localhost")
 	storeType := "NonJavaKeyStore"
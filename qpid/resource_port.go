@@ -9,11 +9,12 @@ import (
 func resourcePort() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createPort,
-		Read:   readPort,
-		Delete: deletePort,
-		Update: updatePort,
-		Exists: existsPort,
+		Create:        createPort,
+		Read:          readPort,
+		Delete:        deletePort,
+		Update:        updatePort,
+		Exists:        existsPort,
+		CustomizeDiff: verifyBrokerCapabilities("port", resourcePort),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -223,12 +224,15 @@ func createPort(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 
 	return fmt.Errorf("error creating qpid port'%s': %s, %v", name, resp.Status, m)
 }
@@ -246,26 +250,26 @@ func readPort(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetPort(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
-	if len(*attributes) == 0 {
-		return nil
-	}
-
 	return applyResourceAttributes(d, attributes)
 }
 
 func existsPort(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetPort(name)
+	_, err := client.GetPort(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
@@ -11,11 +11,12 @@ import (
 
 func resourceQueue() *schema.Resource {
 	return &schema.Resource{
-		Create: createQueue,
-		Read:   readQueue,
-		Delete: deleteQueue,
-		Update: updateQueue,
-		Exists: existsQueue,
+		Create:        createQueue,
+		Read:          readQueue,
+		Delete:        deleteQueue,
+		Update:        updateQueue,
+		Exists:        existsQueue,
+		CustomizeDiff: verifyBrokerCapabilities("queue", resourceQueue, "virtual_host_node", "virtual_host"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -353,7 +354,10 @@ func createQueue(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -391,6 +395,10 @@ func readQueue(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetQueue(node.(string), host.(string), name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -445,13 +453,13 @@ func existsQueue(d *schema.ResourceData, meta interface{}) (bool, error) {
 	}
 
 	name := d.Get("name").(string)
-	attributes, err := client.GetQueue(node.(string), host.(string), name)
+	_, err := client.GetQueue(node.(string), host.(string), name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 
 	return true, nil
 }
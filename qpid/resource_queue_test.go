@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"net/http"
+	"regexp"
 	"testing"
 )
 
@@ -37,6 +38,12 @@ func TestAcceptanceQueue(t *testing.T) {
 					testAcceptanceQueueResource, &map[string]interface{}{"minimumMessageTtl": 1000.0, "maximumMessageTtl": 99999.0},
 				),
 			},
+			{
+				// attribute of other queue type is rejected by the plan verified against the broker metadata
+				Config:      getQueueConfigurationWithAttributes(&map[string]string{"priorities": "5"}),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`attribute 'priorities' of queue type 'standard' is not supported by broker`),
+			},
 			{
 				// update with alternate binding
 				Config: testAcceptanceVirtualHostConfigMinimal + testAcceptanceQueue2 + `
@@ -9,11 +9,12 @@ import (
 func resourceTrustStore() *schema.Resource {
 
 	return &schema.Resource{
-		Create: createTrustStore,
-		Read:   readTrustStore,
-		Delete: deleteTrustStore,
-		Update: updateTrustStore,
-		Exists: existsTrustStore,
+		Create:        createTrustStore,
+		Read:          readTrustStore,
+		Delete:        deleteTrustStore,
+		Update:        updateTrustStore,
+		Exists:        existsTrustStore,
+		CustomizeDiff: verifyBrokerCapabilities("truststore", resourceTrustStore),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -183,12 +184,15 @@ func createTrustStore(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 
 	return fmt.Errorf("error creating qpid trust store'%s': %s, %v", name, resp.Status, m)
 }
@@ -204,6 +208,10 @@ func readTrustStore(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetTrustStore(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -213,13 +221,13 @@ func readTrustStore(d *schema.ResourceData, meta interface{}) error {
 func existsTrustStore(d *schema.ResourceData, meta interface{}) (bool, error) {
 	client := meta.(*Client)
 	name := d.Get("name").(string)
-	attributes, err := client.GetTrustStore(name)
+	_, err := client.GetTrustStore(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if len(*attributes) == 0 {
-		return false, nil
-	}
 	return true, nil
 }
 
@@ -259,7 +267,7 @@ func updateTrustStore(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("qpid  trust store '%s' does not exist", name)
 	}
 
-	m, _ := getErrorResponse(resp)
+	m := newQpidAPIError(resp).Message
 
 	return fmt.Errorf("error updating qpid trust store '%s': %s : %v", name, resp.Status, m)
 }
@@ -9,11 +9,12 @@ import (
 
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		Create: createUser,
-		Read:   readUser,
-		Delete: deleteUser,
-		Update: updateUser,
-		Exists: existsUser,
+		Create:        createUser,
+		Read:          readUser,
+		Delete:        deleteUser,
+		Update:        updateUser,
+		Exists:        existsUser,
+		CustomizeDiff: verifyBrokerCapabilities("user", resourceUser, "authentication_provider"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -101,7 +102,10 @@ func createUser(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -124,6 +128,10 @@ func readUser(d *schema.ResourceData, meta interface{}) error {
 
 	attributes, err := client.GetUser(authenticationProvider, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -136,12 +144,15 @@ func existsUser(d *schema.ResourceData, meta interface{}) (bool, error) {
 
 	name := d.Get("name").(string)
 	authenticationProvider := d.Get("authentication_provider").(string)
-	attributes, err := client.GetUser(authenticationProvider, name)
+	_, err := client.GetUser(authenticationProvider, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteUser(d *schema.ResourceData, meta interface{}) error {
@@ -9,11 +9,12 @@ import (
 
 func resourceVirtualHostAlias() *schema.Resource {
 	return &schema.Resource{
-		Create: createVirtualHostAlias,
-		Read:   readVirtualHostAlias,
-		Delete: deleteVirtualHostAlias,
-		Update: updateVirtualHostAlias,
-		Exists: existsVirtualHostAlias,
+		Create:        createVirtualHostAlias,
+		Read:          readVirtualHostAlias,
+		Delete:        deleteVirtualHostAlias,
+		Update:        updateVirtualHostAlias,
+		Exists:        existsVirtualHostAlias,
+		CustomizeDiff: verifyBrokerCapabilities("virtualhostalias", resourceVirtualHostAlias, "port"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -114,7 +115,10 @@ func createVirtualHostAlias(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -137,6 +141,10 @@ func readVirtualHostAlias(d *schema.ResourceData, meta interface{}) error {
 
 	attributes, err := client.GetVirtualHostAlias(port, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -149,12 +157,15 @@ func existsVirtualHostAlias(d *schema.ResourceData, meta interface{}) (bool, err
 
 	name := d.Get("name").(string)
 	port := d.Get("port").(string)
-	attributes, err := client.GetVirtualHostAlias(port, name)
+	_, err := client.GetVirtualHostAlias(port, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteVirtualHostAlias(d *schema.ResourceData, meta interface{}) error {
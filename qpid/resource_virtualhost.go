@@ -9,11 +9,12 @@ import (
 
 func resourceVirtualHost() *schema.Resource {
 	return &schema.Resource{
-		Create: createVirtualHost,
-		Read:   readVirtualHost,
-		Delete: deleteVirtualHost,
-		Update: updateVirtualHost,
-		Exists: existsVirtualHost,
+		Create:        createVirtualHost,
+		Read:          readVirtualHost,
+		Delete:        deleteVirtualHost,
+		Update:        updateVirtualHost,
+		Exists:        existsVirtualHost,
+		CustomizeDiff: verifyBrokerCapabilities("virtualhost", resourceVirtualHost, "virtual_host_node", "node_auto_creation_policy"),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -314,7 +315,10 @@ func createVirtualHost(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -356,6 +360,10 @@ func readVirtualHost(d *schema.ResourceData, meta interface{}) error {
 
 	attributes, err := client.GetVirtualHost(node, name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -393,12 +401,15 @@ func existsVirtualHost(d *schema.ResourceData, meta interface{}) (bool, error) {
 
 	name := d.Get("name").(string)
 	node := d.Get("virtual_host_node").(string)
-	attributes, err := client.GetVirtualHost(node, name)
+	_, err := client.GetVirtualHost(node, name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	return len(*attributes) > 0, nil
+	return true, nil
 }
 
 func deleteVirtualHost(d *schema.ResourceData, meta interface{}) error {
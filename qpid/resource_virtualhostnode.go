@@ -10,11 +10,12 @@ import (
 
 func resourceVirtualHostNode() *schema.Resource {
 	return &schema.Resource{
-		Create: createVirtualHostNode,
-		Read:   readVirtualHostNode,
-		Delete: deleteVirtualHostNode,
-		Update: updateVirtualHostNode,
-		Exists: existsVirtualHostNode,
+		Create:        createVirtualHostNode,
+		Read:          readVirtualHostNode,
+		Delete:        deleteVirtualHostNode,
+		Update:        updateVirtualHostNode,
+		Exists:        existsVirtualHostNode,
+		CustomizeDiff: verifyBrokerCapabilities("virtualhostnode", resourceVirtualHostNode),
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -244,7 +245,10 @@ func createVirtualHostNode(d *schema.ResourceData, meta interface{}) error {
 				return err
 			}
 		}
-		id := (*attributes)["id"].(string)
+		id, err := objectID(attributes)
+		if err != nil {
+			return err
+		}
 		d.SetId(id)
 		return nil
 	}
@@ -262,6 +266,10 @@ func readVirtualHostNode(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	attributes, err := client.GetVirtualHostNode(name)
 	if err != nil {
+		if IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -294,11 +302,14 @@ func existsVirtualHostNode(d *schema.ResourceData, meta interface{}) (bool, erro
 	client := meta.(*Client)
 
 	name := d.Get("name").(string)
-	attributes, err := client.GetVirtualHostNode(name)
+	_, err := client.GetVirtualHostNode(name)
+	if IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	return len(*attributes) != 0, nil
+	return true, nil
 }
 
 func deleteVirtualHostNode(d *schema.ResourceData, meta interface{}) error {
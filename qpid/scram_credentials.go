@@ -0,0 +1,289 @@
+package qpid
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"hash"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// supported SCRAM mechanisms and their hash functions
+var scramMechanisms = map[string]func() hash.Hash{
+	"SCRAM-SHA-1":   sha1.New,
+	"SCRAM-SHA-256": sha256.New,
+}
+
+// ScramCredentials authenticates with SCRAM SASL mechanism using the broker endpoint /service/sasl
+// and sets the resulting management session cookies on the requests.
+// The login is performed on first request to every broker host and repeated after invalidation of the session.
+type ScramCredentials struct {
+	Username  string
+	Password  string
+	Mechanism string
+
+	httpClient *http.Client
+	mutex      sync.Mutex
+	sessions   map[string][]*http.Cookie
+}
+
+// NewScramCredentials constructs ScramCredentials performing SASL exchange via given transport
+func NewScramCredentials(username string, password string, mechanism string, transport http.RoundTripper) (*Credentials, error) {
+	if _, supported := scramMechanisms[mechanism]; !supported {
+		return nil, fmt.Errorf("unsupported SASL mechanism '%s'", mechanism)
+	}
+
+	var me Credentials = &ScramCredentials{
+		Username:   username,
+		Password:   password,
+		Mechanism:  mechanism,
+		httpClient: &http.Client{Transport: transport},
+		sessions:   map[string][]*http.Cookie{},
+	}
+
+	return &me, nil
+}
+
+// Set management session cookies on http request, logging in if required
+func (c *ScramCredentials) Set(request *http.Request) error {
+	saslURL := saslServiceURL(request.URL)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cookies, loggedIn := c.sessions[saslURL]
+	if !loggedIn {
+		var err error
+		cookies, err = c.login(saslURL)
+		if err != nil {
+			return err
+		}
+		c.sessions[saslURL] = cookies
+	}
+
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	return nil
+}
+
+// Invalidate discards all management sessions, thus, the next request logs in again
+func (c *ScramCredentials) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sessions = map[string][]*http.Cookie{}
+}
+
+// String ...
+func (c *ScramCredentials) String() string {
+	return "ScramCredentials [username: " + c.Username + ", mechanism: " + c.Mechanism + "]"
+}
+
+// saslServiceURL returns URL of SASL service for the broker serving given REST API URL
+func saslServiceURL(u *url.URL) string {
+	path := u.Path
+	if idx := strings.Index(path, "/api/"); idx >= 0 {
+		path = path[:idx]
+	}
+	return u.Scheme + "://" + u.Host + strings.TrimSuffix(path, "/") + "/service/sasl"
+}
+
+func (c *ScramCredentials) login(saslURL string) ([]*http.Cookie, error) {
+	log.Printf("[DEBUG] Qpid: %s login into %s as %s", c.Mechanism, saslURL, c.Username)
+
+	scram, err := newScramClient(c.Mechanism, c.Username, c.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies := map[string]*http.Cookie{}
+	step, err := c.submitSaslResponse(saslURL, url.Values{
+		"mechanism": {c.Mechanism},
+		"response":  {base64.StdEncoding.EncodeToString([]byte(scram.clientFirstMessage()))},
+	}, cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	serverFirst, err := base64.StdEncoding.DecodeString(step.Challenge)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s server challenge: %v", c.Mechanism, err)
+	}
+
+	clientFinal, err := scram.clientFinalMessage(string(serverFirst))
+	if err != nil {
+		return nil, err
+	}
+
+	step, err = c.submitSaslResponse(saslURL, url.Values{
+		"id":       {step.ID},
+		"response": {base64.StdEncoding.EncodeToString([]byte(clientFinal))},
+	}, cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	// the server final message carries the server signature, the broker is not authenticated without it
+	if step.AdditionalData == "" {
+		return nil, fmt.Errorf("%s server final message is missing, the server signature cannot be verified", c.Mechanism)
+	}
+	serverFinal, err := base64.StdEncoding.DecodeString(step.AdditionalData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s server final message: %v", c.Mechanism, err)
+	}
+	err = scram.verifyServerFinalMessage(string(serverFinal))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		result = append(result, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return result, nil
+}
+
+type saslStep struct {
+	ID             string `json:"id"`
+	Challenge      string `json:"challenge"`
+	AdditionalData string `json:"additionalData"`
+}
+
+func (c *ScramCredentials) submitSaslResponse(saslURL string, form url.Values, cookies map[string]*http.Cookie) (*saslStep, error) {
+	req, err := http.NewRequest(http.MethodPost, saslURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s authentication of '%s' failed: %s", c.Mechanism, c.Username, resp.Status)
+	}
+
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	step := saslStep{}
+	err = json.NewDecoder(resp.Body).Decode(&step)
+	if err != nil && resp.ContentLength != 0 {
+		return nil, fmt.Errorf("unable to decode SASL response: %v", err)
+	}
+	return &step, nil
+}
+
+// scramClient implements client side of SCRAM exchange as defined in RFC 5802
+type scramClient struct {
+	hash        func() hash.Hash
+	username    string
+	password    string
+	clientNonce string
+
+	clientFirstMessageBare string
+	serverSignature        []byte
+}
+
+func newScramClient(mechanism string, username string, password string) (*scramClient, error) {
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &scramClient{
+		hash:        scramMechanisms[mechanism],
+		username:    username,
+		password:    password,
+		clientNonce: base64.RawStdEncoding.EncodeToString(nonce),
+	}, nil
+}
+
+func (s *scramClient) clientFirstMessage() string {
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s.username)
+	s.clientFirstMessageBare = "n=" + username + ",r=" + s.clientNonce
+	return "n,," + s.clientFirstMessageBare
+}
+
+func (s *scramClient) clientFinalMessage(serverFirstMessage string) (string, error) {
+	attributes := parseScramAttributes(serverFirstMessage)
+
+	nonce := attributes["r"]
+	if !strings.HasPrefix(nonce, s.clientNonce) || len(nonce) == len(s.clientNonce) {
+		return "", fmt.Errorf("invalid server nonce in SCRAM challenge")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(attributes["s"])
+	if err != nil {
+		return "", fmt.Errorf("invalid salt in SCRAM challenge: %v", err)
+	}
+
+	iterations, err := strconv.Atoi(attributes["i"])
+	if err != nil || iterations < 1 {
+		return "", fmt.Errorf("invalid iteration count in SCRAM challenge: '%s'", attributes["i"])
+	}
+
+	saltedPassword := pbkdf2.Key([]byte(s.password), salt, iterations, s.hash().Size(), s.hash)
+	clientKey := s.hmac(saltedPassword, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+	serverKey := s.hmac(saltedPassword, "Server Key")
+
+	clientFinalMessageWithoutProof := "c=biws,r=" + nonce
+	authMessage := s.clientFirstMessageBare + "," + serverFirstMessage + "," + clientFinalMessageWithoutProof
+
+	clientSignature := s.hmac(storedKey.Sum(nil), authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	s.serverSignature = s.hmac(serverKey, authMessage)
+
+	return clientFinalMessageWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (s *scramClient) verifyServerFinalMessage(serverFinalMessage string) error {
+	attributes := parseScramAttributes(serverFinalMessage)
+	if e, failed := attributes["e"]; failed {
+		return fmt.Errorf("SCRAM authentication failed: %s", e)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(attributes["v"])
+	if err != nil || !hmac.Equal(signature, s.serverSignature) {
+		return fmt.Errorf("SCRAM server signature verification failed")
+	}
+	return nil
+}
+
+func (s *scramClient) hmac(key []byte, message string) []byte {
+	mac := hmac.New(s.hash, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func parseScramAttributes(message string) map[string]string {
+	attributes := map[string]string{}
+	for _, part := range strings.Split(message, ",") {
+		if len(part) > 2 && part[1] == '=' {
+			attributes[part[:1]] = part[2:]
+		}
+	}
+	return attributes
+}
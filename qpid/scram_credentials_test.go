@@ -0,0 +1,147 @@
+package qpid
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestScramClientTestVectors(t *testing.T) {
+	// test vectors of RFC 5802 and RFC 7677
+	vectors := []struct {
+		mechanism   string
+		clientNonce string
+		serverFirst string
+		clientFinal string
+		serverFinal string
+	}{
+		{
+			mechanism:   "SCRAM-SHA-1",
+			clientNonce: "fyko+d2lbbFgONRv9qkxdawL",
+			serverFirst: "r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096",
+			clientFinal: "c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+			serverFinal: "v=rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+		},
+		{
+			mechanism:   "SCRAM-SHA-256",
+			clientNonce: "rOprNGfwEbeRWgbNEkqO",
+			serverFirst: "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096",
+			clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+			serverFinal: "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		},
+	}
+
+	for _, vector := range vectors {
+		scram := &scramClient{hash: scramMechanisms[vector.mechanism], username: "user", password: "pencil", clientNonce: vector.clientNonce}
+		if first := scram.clientFirstMessage(); first != "n,,n=user,r="+vector.clientNonce {
+			t.Errorf("unexpected %s client first message '%s'", vector.mechanism, first)
+		}
+		final, err := scram.clientFinalMessage(vector.serverFirst)
+		if err != nil || final != vector.clientFinal {
+			t.Errorf("unexpected %s client final message '%s': %v", vector.mechanism, final, err)
+		}
+		if err = scram.verifyServerFinalMessage(vector.serverFinal); err != nil {
+			t.Errorf("valid %s server signature is rejected: %v", vector.mechanism, err)
+		}
+		if err = scram.verifyServerFinalMessage("v=" + base64.StdEncoding.EncodeToString([]byte("forged"))); err == nil {
+			t.Errorf("forged %s server signature is accepted", vector.mechanism)
+		}
+	}
+}
+
+// fakeSaslExchange holds the state of SCRAM exchange on the server side. The server computes the expected
+// client proof and server signature with a client holding the password stored by the server.
+type fakeSaslExchange struct {
+	client      *scramClient
+	serverFirst string
+}
+
+// newFakeSaslServer returns a server performing the server side of SCRAM exchange on /service/sasl.
+// The server final message is modified by the given function before sending it to the client.
+func newFakeSaslServer(mechanism string, username string, password string, serverFinal func(string) string) *httptest.Server {
+	var mutex sync.Mutex
+	exchanges := map[string]*fakeSaslExchange{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, _ := base64.StdEncoding.DecodeString(r.FormValue("response"))
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if r.FormValue("mechanism") != "" {
+			nonce := attributeOf(strings.TrimPrefix(string(response), "n,,"), "r")
+			exchange := &fakeSaslExchange{
+				client:      &scramClient{hash: scramMechanisms[mechanism], username: username, password: password, clientNonce: nonce},
+				serverFirst: "r=" + nonce + "server,s=" + base64.StdEncoding.EncodeToString([]byte("salt")) + ",i=4096",
+			}
+			exchange.client.clientFirstMessage()
+			id := strconv.Itoa(len(exchanges))
+			exchanges[id] = exchange
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "session"})
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": id, "challenge": base64.StdEncoding.EncodeToString([]byte(exchange.serverFirst))})
+			return
+		}
+
+		exchange, found := exchanges[r.FormValue("id")]
+		if !found {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		expected, err := exchange.client.clientFinalMessage(exchange.serverFirst)
+		if err != nil || attributeOf(string(response), "p") != attributeOf(expected, "p") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		result := map[string]string{}
+		if final := serverFinal("v=" + base64.StdEncoding.EncodeToString(exchange.client.serverSignature)); final != "" {
+			result["additionalData"] = base64.StdEncoding.EncodeToString([]byte(final))
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+}
+
+func attributeOf(message string, name string) string {
+	return parseScramAttributes(message)[name]
+}
+
+func TestScramCredentialsLogin(t *testing.T) {
+	serverFinals := map[string]func(string) string{
+		"":                                     func(final string) string { return final },
+		"server final message is missing":      func(string) string { return "" },
+		"server signature verification failed": func(string) string { return "v=" + base64.StdEncoding.EncodeToString([]byte("forged")) },
+		"authentication failed: other-error":   func(string) string { return "e=other-error" },
+	}
+
+	for expected, serverFinal := range serverFinals {
+		server := newFakeSaslServer("SCRAM-SHA-256", "user", "pencil", serverFinal)
+		credentials, err := NewScramCredentials("user", "pencil", "SCRAM-SHA-256", http.DefaultTransport)
+		if err != nil {
+			t.Fatalf("unable to create credentials: %v", err)
+		}
+
+		u, _ := url.Parse(server.URL + "/api/latest/broker")
+		req := &http.Request{URL: u, Header: http.Header{}}
+		err = (*credentials).Set(req)
+		if expected == "" {
+			if cookie, cookieErr := req.Cookie("JSESSIONID"); err != nil || cookieErr != nil || cookie.Value != "session" {
+				t.Errorf("unexpected result of login: %v", err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("unexpected login error '%v', expected '%s'", err, expected)
+		}
+		server.Close()
+	}
+
+	server := newFakeSaslServer("SCRAM-SHA-256", "user", "other", func(final string) string { return final })
+	defer server.Close()
+	credentials, _ := NewScramCredentials("user", "pencil", "SCRAM-SHA-256", http.DefaultTransport)
+	u, _ := url.Parse(server.URL + "/api/latest/broker")
+	err := (*credentials).Set(&http.Request{URL: u, Header: http.Header{}})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("login with wrong password succeeded: %v", err)
+	}
+}
@@ -2,12 +2,19 @@ package qpid
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"io/ioutil"
 	"log"
+	"math/rand"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,6 +23,13 @@ type Credentials interface {
 	Set(request *http.Request) error
 }
 
+// InvalidatableCredentials represent credentials holding a cached token or session.
+// The cached state is discarded when rejected by the broker, thus, it is obtained again on the next request.
+type InvalidatableCredentials interface {
+	Credentials
+	Invalidate()
+}
+
 // BasicAuthCredentials represents basic authentication credentials
 type BasicAuthCredentials struct {
 	Username string
@@ -24,7 +38,6 @@ type BasicAuthCredentials struct {
 
 // Set basic authentication credentials on http request
 func (c BasicAuthCredentials) Set(request *http.Request) error {
-	log.Printf("[DEBUG] Qpid: BasicAuthCredentials %s", c.Username)
 	request.SetBasicAuth(c.Username, c.Password)
 	return nil
 }
@@ -44,38 +57,178 @@ func NewBasicAuthCredentials(username string, password string) *Credentials {
 	return &me
 }
 
-// SimpleRestClient is a basic REST client for calling REST API using GET/POST/PUT/DELETE methods
+// BearerTokenCredentials represents a static bearer token, for example, an OAuth2 access token
+type BearerTokenCredentials struct {
+	Token string
+}
+
+// Set bearer token authorization header on http request
+func (c BearerTokenCredentials) Set(request *http.Request) error {
+	request.Header.Set("Authorization", "Bearer "+c.Token)
+	return nil
+}
+
+// String ...
+func (c *BearerTokenCredentials) String() string {
+	return "BearerTokenCredentials"
+}
+
+// NewBearerTokenCredentials constructs BearerTokenCredentials
+func NewBearerTokenCredentials(token string) *Credentials {
+	var me Credentials = BearerTokenCredentials{
+		Token: token,
+	}
+
+	return &me
+}
+
+// RetryPolicy defines how many times and how often idempotent requests are retried
+// when broker is temporarily unavailable
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Jitter      bool
+}
+
+// NoRetryPolicy makes a single attempt for each request
+var NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// backoff returns the delay before the given retry attempt (starting from 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter && delay > 1 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
+
+// anyEndpoint selects the active endpoint with failing over to the other endpoints
+const anyEndpoint = -1
+
+// pinnedEndpointKey marks the requests which must be sent to their endpoint without failing over
+type pinnedEndpointKey struct{}
+
+// SimpleRestClient is a basic REST client for calling REST API using GET/POST/PUT/DELETE methods.
+// When several endpoints are given, requests fail over to the next endpoint if the active one is unreachable.
+// The management session cookies set by the broker are kept and reused, thus, the credentials are only sent
+// when there is no session with the endpoint or the session is rejected by the broker.
 type SimpleRestClient struct {
-	endpoint    *url.URL
+	endpoints   []*url.URL
+	basePath    string
+	active      int
+	mutex       sync.RWMutex
 	credentials *Credentials
-	transport   http.RoundTripper
-	timeout     time.Duration
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	limiter     *requestLimiter
+	sessions    *cookiejar.Jar
 }
 
 // NewSimpleRestClient creates a client  for given URI, credentials, and transport
 func NewSimpleRestClient(uri string, credentials *Credentials, transport http.RoundTripper) (me *SimpleRestClient, err error) {
-	u, err := url.Parse(uri)
-	if err != nil {
-		return &SimpleRestClient{}, err
+	return NewSimpleRestClientForEndpoints([]string{uri}, credentials, transport)
+}
+
+// NewSimpleRestClientForEndpoints creates a client for given URIs of equivalent endpoints, credentials, and transport
+func NewSimpleRestClientForEndpoints(uris []string, credentials *Credentials, transport http.RoundTripper) (me *SimpleRestClient, err error) {
+	if len(uris) == 0 {
+		return &SimpleRestClient{}, errors.New("at least one endpoint is required")
+	}
+
+	endpoints := make([]*url.URL, len(uris))
+	for i, uri := range uris {
+		endpoints[i], err = url.Parse(uri)
+		if err != nil {
+			return &SimpleRestClient{}, err
+		}
 	}
 
+	// single http client is shared by all requests in order to keep connections alive between requests
 	me = &SimpleRestClient{
-		endpoint:    u,
+		endpoints:   endpoints,
 		credentials: credentials,
-		transport:   transport,
+		httpClient:  &http.Client{Transport: transport},
+		retryPolicy: NoRetryPolicy,
+		limiter:     newRequestLimiter(0, 0),
 	}
+	me.sessions, err = cookiejar.New(nil)
+
+	return me, err
+}
+
+// SetBasePath sets the path prefix appended to the endpoint URI for all requests except the ones with full path
+func (c *SimpleRestClient) SetBasePath(basePath string) {
+	c.basePath = strings.Trim(basePath, "/")
+}
 
-	return me, nil
+// EndpointCount returns number of endpoints
+func (c *SimpleRestClient) EndpointCount() int {
+	return len(c.endpoints)
+}
+
+// ActiveEndpoint returns index of the endpoint the requests are sent to
+func (c *SimpleRestClient) ActiveEndpoint() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.active
+}
+
+// SetActiveEndpoint sets index of the endpoint to send the requests to
+func (c *SimpleRestClient) SetActiveEndpoint(index int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.active != index {
+		log.Printf("[INFO] Qpid: switching active endpoint from %s to %s", c.endpoints[c.active].Host, c.endpoints[index].Host)
+		c.active = index
+	}
 }
 
 // SetTransport sets transport
 func (c *SimpleRestClient) SetTransport(transport http.RoundTripper) {
-	c.transport = transport
+	c.httpClient.Transport = transport
 }
 
 // SetTimeout sets timeout
 func (c *SimpleRestClient) SetTimeout(timeout time.Duration) {
-	c.timeout = timeout
+	c.httpClient.Timeout = timeout
+}
+
+// SetRetryPolicy sets the policy for retrying of idempotent requests
+func (c *SimpleRestClient) SetRetryPolicy(retryPolicy RetryPolicy) {
+	c.retryPolicy = retryPolicy
+}
+
+// SetRequestLimits sets the maximum number of concurrent requests and the maximum number of requests per second,
+// zero values mean no limit. Requests exceeding the limits wait until they can be sent.
+func (c *SimpleRestClient) SetRequestLimits(maxConcurrentRequests int, requestsPerSecond float64) {
+	c.limiter = newRequestLimiter(maxConcurrentRequests, requestsPerSecond)
+}
+
+// GetAsMapFromEndpoint sends GET request to the given path of the endpoint with given index without failing over
+func (c *SimpleRestClient) GetAsMapFromEndpoint(index int, path string, query url.Values) (*map[string]interface{}, error) {
+	req, err := c.newHTTPRequestForEndpoint(index, http.MethodGet, path+"?"+query.Encode(), nil)
+	if err != nil {
+		return &map[string]interface{}{}, err
+	}
+
+	res, err := c.do(req)
+	if err == nil && res.StatusCode == http.StatusUnauthorized && c.reauthenticate(req) {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		_ = res.Body.Close()
+		res, err = c.do(req)
+	}
+	if err != nil {
+		return &map[string]interface{}{}, err
+	}
+
+	return convertHttpResponseToMap(res)
 }
 
 // GetAsMap sends GET request to the given path and returns results as map
@@ -85,7 +238,7 @@ func (c *SimpleRestClient) GetAsMap(path string, query url.Values) (*map[string]
 		return &map[string]interface{}{}, err
 	}
 
-	res, err := c.executeHTTPRequest(req)
+	res, err := c.executeHTTPRequest(req, true)
 	if err != nil {
 		return &map[string]interface{}{}, err
 	}
@@ -95,17 +248,21 @@ func (c *SimpleRestClient) GetAsMap(path string, query url.Values) (*map[string]
 
 // Submit sends given map of attributes to the server into given path using given method
 func (c *SimpleRestClient) Submit(method string, path string, attributes *map[string]interface{}) (res *http.Response, err error) {
+	return c.submit(anyEndpoint, method, path, attributes, false)
+}
+
+func (c *SimpleRestClient) submit(index int, method string, path string, attributes *map[string]interface{}, idempotent bool) (res *http.Response, err error) {
 	body, err := json.Marshal(*attributes)
 	if err != nil {
 		return &http.Response{}, err
 	}
 
-	req, err := c.newHTTPRequest(method, path, body)
+	req, err := c.newHTTPRequestForTarget(index, method, path, body)
 	if err != nil {
 		return &http.Response{}, err
 	}
 
-	return c.executeHTTPRequest(req)
+	return c.executeBufferedHTTPRequest(req, idempotent)
 }
 
 // Post post attribute map into given path
@@ -113,6 +270,23 @@ func (c *SimpleRestClient) Post(path string, attributes *map[string]interface{})
 	return c.Submit(http.MethodPost, path, attributes)
 }
 
+// PostToEndpoint posts attribute map into given path of the endpoint with given index.
+// The request neither fails over nor changes the active endpoint, anyEndpoint index selects the active endpoint.
+func (c *SimpleRestClient) PostToEndpoint(index int, path string, attributes *map[string]interface{}) (res *http.Response, err error) {
+	return c.submit(index, http.MethodPost, path, attributes, false)
+}
+
+// Update posts attribute changes into given object path.
+// Unlike Post, the request is considered idempotent and can be retried.
+func (c *SimpleRestClient) Update(path string, attributes *map[string]interface{}) (res *http.Response, err error) {
+	return c.UpdateOnEndpoint(anyEndpoint, path, attributes)
+}
+
+// UpdateOnEndpoint posts attribute changes into given object path of the endpoint with given index
+func (c *SimpleRestClient) UpdateOnEndpoint(index int, path string, attributes *map[string]interface{}) (res *http.Response, err error) {
+	return c.submit(index, http.MethodPost, path, attributes, true)
+}
+
 // Put puts attribute map into given path
 func (c *SimpleRestClient) Put(path string, attributes *map[string]interface{}) (res *http.Response, err error) {
 	return c.Submit(http.MethodPut, path, attributes)
@@ -120,12 +294,17 @@ func (c *SimpleRestClient) Put(path string, attributes *map[string]interface{})
 
 // Delete deletes the resource
 func (c *SimpleRestClient) Delete(path string) (*http.Response, error) {
-	req, err := c.newHTTPRequest(http.MethodDelete, path, nil)
+	return c.DeleteFromEndpoint(anyEndpoint, path)
+}
+
+// DeleteFromEndpoint deletes the resource on the endpoint with given index
+func (c *SimpleRestClient) DeleteFromEndpoint(index int, path string) (*http.Response, error) {
+	req, err := c.newHTTPRequestForTarget(index, http.MethodDelete, path, nil)
 	if err != nil {
 		return &http.Response{}, err
 	}
 
-	return c.executeHTTPRequest(req)
+	return c.executeBufferedHTTPRequest(req, true)
 }
 
 func (c *SimpleRestClient) newGetHTTPRequest(path string) (*http.Request, error) {
@@ -137,18 +316,39 @@ func (c *SimpleRestClient) newGetHTTPRequestWithParameters(path string, qs url.V
 }
 
 func (c *SimpleRestClient) newHTTPRequest(method string, path string, body []byte) (*http.Request, error) {
+	return c.newHTTPRequestForEndpoint(c.ActiveEndpoint(), method, path, body)
+}
+
+// newHTTPRequestForTarget creates request for the endpoint with given index which is sent without failing over,
+// or for the active endpoint with failing over when the index is anyEndpoint
+func (c *SimpleRestClient) newHTTPRequestForTarget(index int, method string, path string, body []byte) (*http.Request, error) {
+	if index == anyEndpoint {
+		return c.newHTTPRequest(method, path, body)
+	}
+
+	req, err := c.newHTTPRequestForEndpoint(index, method, path, body)
+	if err != nil {
+		return req, err
+	}
+	return req.WithContext(context.WithValue(req.Context(), pinnedEndpointKey{}, true)), nil
+}
+
+func (c *SimpleRestClient) newHTTPRequestForEndpoint(index int, method string, path string, body []byte) (*http.Request, error) {
 	var b io.Reader = nil
 	if body != nil {
 		b = bytes.NewReader(body)
 	}
 
-	uri := c.endpoint.String() + "/" + path
+	// full paths starting with slash are not prefixed with the base path
+	uri := c.endpoints[index].String() + "/" + path
+	if strings.HasPrefix(path, "/") {
+		uri = c.endpoints[index].String() + path
+	} else if c.basePath != "" {
+		uri = c.endpoints[index].String() + "/" + c.basePath + "/" + path
+	}
 
-	credentials := (*c).credentials
-	log.Printf("new http request to %s with credentials %v", uri, credentials)
 	req, err := http.NewRequest(method, uri, b)
-	req.Close = true
-	if err == nil {
+	if err == nil && c.credentials != nil && !c.hasSession(req.URL) {
 		err = (*c.credentials).Set(req)
 	}
 
@@ -158,30 +358,263 @@ func (c *SimpleRestClient) newHTTPRequest(method string, path string, body []byt
 	return req, err
 }
 
-func (c *SimpleRestClient) executeHTTPRequest(req *http.Request) (res *http.Response, err error) {
-	httpClient := &http.Client{
-		Timeout: c.timeout,
+func (c *SimpleRestClient) executeHTTPRequest(req *http.Request, idempotent bool) (res *http.Response, err error) {
+	maxAttempts := 1
+	if idempotent && c.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = c.retryPolicy.MaxAttempts
 	}
-	if c.transport != nil {
-		httpClient.Transport = c.transport
+
+	var resp *http.Response
+	for attempt := 1; ; attempt++ {
+		resp, err = c.doWithFailover(req, idempotent)
+		if attempt >= maxAttempts || !isRetryable(resp, err) {
+			break
+		}
+
+		if err == nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, bodyErr
+			}
+			req.Body = body
+		}
+
+		delay := c.retryPolicy.backoff(attempt)
+		log.Printf("[DEBUG] Qpid: %s %s failed (attempt %d of %d), retrying in %v: %v", req.Method, req.URL.Path, attempt, maxAttempts, delay, describeFailure(resp, err))
+		time.Sleep(delay)
 	}
-	resp, err := httpClient.Do(req)
+
 	if err != nil {
 		return resp, err
 	}
+	if resp.StatusCode == http.StatusUnauthorized && c.reauthenticate(req) {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+		resp, err = c.do(req)
+		if err != nil {
+			return resp, err
+		}
+	}
 	if resp.StatusCode == 401 {
+		_ = resp.Body.Close()
 		return resp, errors.New("error: 401 unauthorized")
 	}
 	return resp, err
 }
 
+// doWithFailover sends the request to the active endpoint, failing over to the next endpoints when the request fails.
+// Non-idempotent requests only fail over when connection to the endpoint cannot be established.
+func (c *SimpleRestClient) doWithFailover(req *http.Request, idempotent bool) (*http.Response, error) {
+	if pinned, _ := req.Context().Value(pinnedEndpointKey{}).(bool); pinned {
+		return c.do(req)
+	}
+
+	current := c.endpointIndex(req.URL)
+	for i := 0; ; i++ {
+		resp, err := c.do(req)
+		if err == nil {
+			c.SetActiveEndpoint(current)
+			return resp, nil
+		}
+
+		if i+1 >= len(c.endpoints) || current < 0 || !(idempotent || isConnectionFailure(err)) {
+			return resp, err
+		}
+
+		next := (current + 1) % len(c.endpoints)
+		log.Printf("[WARN] Qpid: %s %s failed on %s, failing over to %s: %v", req.Method, req.URL.Path, c.endpoints[current].Host, c.endpoints[next].Host, err)
+		if retargetErr := c.retarget(req, current, next); retargetErr != nil {
+			return resp, err
+		}
+		current = next
+	}
+}
+
+// do sends the request once the request limits allow it, adding the session cookies to the request
+// and keeping the session cookies from the response. The request slot is released when the response body is closed.
+func (c *SimpleRestClient) do(req *http.Request) (*http.Response, error) {
+	release := c.limiter.acquire()
+
+	jar := c.sessionJar()
+	for _, cookie := range jar.Cookies(req.URL) {
+		if _, err := req.Cookie(cookie.Name); err == http.ErrNoCookie {
+			req.AddCookie(cookie)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		release()
+		return resp, err
+	}
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		jar.SetCookies(req.URL, cookies)
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// sessionJar returns the jar holding the session cookies
+func (c *SimpleRestClient) sessionJar() *cookiejar.Jar {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.sessions
+}
+
+// hasSession returns true if there are session cookies for given URL
+func (c *SimpleRestClient) hasSession(u *url.URL) bool {
+	return len(c.sessionJar().Cookies(u)) > 0
+}
+
+// discardSessions discards all session cookies when the session for given URL is one of the rejected cookies.
+// The sessions renewed by concurrent requests after the rejection are kept.
+func (c *SimpleRestClient) discardSessions(u *url.URL, rejected []*http.Cookie) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, cookie := range c.sessions.Cookies(u) {
+		for _, r := range rejected {
+			if cookie.Name == r.Name && cookie.Value == r.Value {
+				c.sessions, _ = cookiejar.New(nil)
+				return
+			}
+		}
+	}
+}
+
+// endpointIndex returns index of the endpoint given URL belongs to
+func (c *SimpleRestClient) endpointIndex(u *url.URL) int {
+	uri := u.String()
+	for i, endpoint := range c.endpoints {
+		if strings.HasPrefix(uri, endpoint.String()+"/") {
+			return i
+		}
+	}
+	return -1
+}
+
+// retarget changes the request URL to point to the same path on another endpoint
+func (c *SimpleRestClient) retarget(req *http.Request, from int, to int) error {
+	relative := strings.TrimPrefix(req.URL.String(), c.endpoints[from].String())
+	u, err := url.Parse(c.endpoints[to].String() + relative)
+	if err != nil {
+		return err
+	}
+	req.URL = u
+	req.Host = u.Host
+
+	// session cookies are endpoint specific
+	req.Header.Del("Cookie")
+	req.Header.Del("Authorization")
+	if c.credentials != nil && !c.hasSession(req.URL) {
+		if err = (*c.credentials).Set(req); err != nil {
+			return err
+		}
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		req.Body = body
+	}
+	return nil
+}
+
+// isConnectionFailure returns true if the error is caused by failure to connect, i.e. the request was not sent
+func isConnectionFailure(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// executeBufferedHTTPRequest executes the request and reads the response body into memory,
+// releasing the underlying connection back into the pool even if the caller never closes the body
+func (c *SimpleRestClient) executeBufferedHTTPRequest(req *http.Request, idempotent bool) (*http.Response, error) {
+	resp, err := c.executeHTTPRequest(req, idempotent)
+	if resp == nil || resp.Body == nil {
+		return resp, err
+	}
+
+	body, readErr := ioutil.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	if readErr != nil {
+		return resp, readErr
+	}
+	return resp, closeErr
+}
+
+// reauthenticate discards the management sessions and cached token or session of invalidatable credentials,
+// and sets fresh credentials on the request. It returns true when the request can be resent.
+// The request is resent when it relied on a session, i.e. it carried a session cookie or no credentials,
+// thus, requests rejected concurrently after the session expiry are resent as well.
+func (c *SimpleRestClient) reauthenticate(req *http.Request) bool {
+	if c.credentials == nil {
+		return false
+	}
+	rejected := req.Cookies()
+	sessionRejected := len(rejected) > 0 || req.Header.Get("Authorization") == ""
+	c.discardSessions(req.URL, rejected)
+	credentials, invalidatable := (*c.credentials).(InvalidatableCredentials)
+	if !invalidatable && !sessionRejected {
+		return false
+	}
+
+	log.Printf("[DEBUG] Qpid: %s %s is unauthorized, re-authenticating", req.Method, req.URL.Path)
+	if invalidatable {
+		credentials.Invalidate()
+	}
+	req.Header.Del("Authorization")
+	req.Header.Del("Cookie")
+	if err := (*c.credentials).Set(req); err != nil {
+		log.Printf("[WARN] Qpid: re-authentication failed: %v", err)
+		return false
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		req.Body = body
+	}
+	return true
+}
+
+// isRetryable returns true when request failed due to transport error or broker being temporarily unavailable
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
 func (c *SimpleRestClient) GetAsArray(path string, query url.Values) (*[]map[string]interface{}, error) {
 	req, err := c.newGetHTTPRequestWithParameters(path, query)
 	if err != nil {
 		return &[]map[string]interface{}{}, err
 	}
 
-	res, err := c.executeHTTPRequest(req)
+	res, err := c.executeHTTPRequest(req, true)
 	if err != nil {
 		return &[]map[string]interface{}{}, err
 	}
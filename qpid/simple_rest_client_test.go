@@ -0,0 +1,244 @@
+package qpid
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingTransport fails the given number of first requests with a connection error
+type failingTransport struct {
+	transport http.RoundTripper
+	failures  int32
+	requests  int32
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.AddInt32(&t.requests, 1) <= t.failures {
+		return nil, errors.New("connection reset by peer")
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// newUnavailableServer returns a server responding with 503 to the given number of first requests
+func newUnavailableServer(unavailable int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "broker"}`))
+	}))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, delay := range expected {
+		if backoff := policy.backoff(i + 1); backoff != delay {
+			t.Errorf("unexpected backoff %v of attempt %d, expected %v", backoff, i+1, delay)
+		}
+	}
+
+	policy.Jitter = true
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := (RetryPolicy{BaseBackoff: policy.BaseBackoff, MaxBackoff: policy.MaxBackoff}).backoff(attempt)
+		if backoff := policy.backoff(attempt); backoff < delay/2 || backoff > delay {
+			t.Errorf("backoff %v of attempt %d with jitter is out of range [%v, %v]", backoff, attempt, delay/2, delay)
+		}
+	}
+}
+
+func TestSimpleRestClientRetryOnUnavailableBroker(t *testing.T) {
+	var requests int32
+	server := newUnavailableServer(2, &requests)
+	defer server.Close()
+
+	client, err := NewSimpleRestClient(server.URL, nil, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	attributes, err := client.GetAsMap("broker", url.Values{})
+	if err != nil || (*attributes)["name"] != "broker" {
+		t.Fatalf("unexpected result of retried request %v: %v", attributes, err)
+	}
+	if requests != 3 {
+		t.Fatalf("unexpected number of requests %d, expected 3", requests)
+	}
+
+	// requests which are not idempotent are not retried
+	atomic.StoreInt32(&requests, 0)
+	resp, err := client.Post("broker", &map[string]interface{}{})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || requests != 1 {
+		t.Fatalf("unexpected result of post after %d requests %v: %v", requests, resp, err)
+	}
+
+	// updates are retried until the attempts are exhausted
+	atomic.StoreInt32(&requests, 0)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	resp, err = client.Update("broker", &map[string]interface{}{})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || requests != 2 {
+		t.Fatalf("unexpected result of update after %d requests %v: %v", requests, resp, err)
+	}
+}
+
+func TestSimpleRestClientRetryOnConnectionError(t *testing.T) {
+	var requests int32
+	server := newUnavailableServer(0, &requests)
+	defer server.Close()
+
+	transport := &failingTransport{transport: http.DefaultTransport, failures: 2}
+	client, err := NewSimpleRestClient(server.URL, nil, transport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	attributes, err := client.GetAsMap("broker", url.Values{})
+	if err != nil || (*attributes)["name"] != "broker" {
+		t.Fatalf("unexpected result of retried request %v: %v", attributes, err)
+	}
+	if transport.requests != 3 || requests != 1 {
+		t.Fatalf("unexpected number of attempts %d and requests %d", transport.requests, requests)
+	}
+
+	client.SetRetryPolicy(NoRetryPolicy)
+	atomic.StoreInt32(&transport.requests, 0)
+	_, err = client.GetAsMap("broker", url.Values{})
+	if err == nil || transport.requests != 1 {
+		t.Fatalf("request is retried without retry policy after %d attempts: %v", transport.requests, err)
+	}
+}
+
+func TestSimpleRestClientFailover(t *testing.T) {
+	var requests int32
+	server := newUnavailableServer(0, &requests)
+	defer server.Close()
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	client, err := NewSimpleRestClientForEndpoints([]string{stopped.URL, server.URL}, nil, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+
+	attributes, err := client.GetAsMap("broker", url.Values{})
+	if err != nil || (*attributes)["name"] != "broker" {
+		t.Fatalf("request is not failed over %v: %v", attributes, err)
+	}
+	if client.ActiveEndpoint() != 1 {
+		t.Fatalf("unexpected active endpoint %d after failover", client.ActiveEndpoint())
+	}
+
+	// the request pinned to the endpoint does not fail over
+	_, err = client.PostToEndpoint(0, "broker", &map[string]interface{}{})
+	if err == nil || client.ActiveEndpoint() != 1 {
+		t.Fatalf("request pinned to stopped endpoint succeeded or changed active endpoint to %d: %v", client.ActiveEndpoint(), err)
+	}
+
+	// request which is not idempotent fails over when the connection cannot be established
+	client.SetActiveEndpoint(0)
+	resp, err := client.Post("broker", &map[string]interface{}{})
+	if err != nil || resp.StatusCode != http.StatusOK || requests != 2 || client.ActiveEndpoint() != 1 {
+		t.Fatalf("unexpected result of post after %d requests %v: %v", requests, resp, err)
+	}
+}
+
+func TestSimpleRestClientPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path": "` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewSimpleRestClient(server.URL+"/context", nil, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	client.SetBasePath("api/latest")
+
+	paths := map[string]string{
+		"broker":            "/context/api/latest/broker",
+		"/service/metadata": "/context/service/metadata",
+	}
+	for path, expected := range paths {
+		attributes, err := client.GetAsMap(path, url.Values{})
+		if err != nil || (*attributes)["path"] != expected {
+			t.Errorf("unexpected path of request to '%s' %v, expected '%s': %v", path, attributes, expected, err)
+		}
+	}
+}
+
+func TestSimpleRestClientRequestSlotHeldUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "broker"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewSimpleRestClient(server.URL, nil, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	client.SetRequestLimits(1, 0)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/latest/broker", nil)
+	resp, err := client.do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetAsMap("broker", url.Values{})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatalf("request is sent while the body of the previous response is not closed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("unable to get broker: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("request is not sent after the body of the previous response is closed")
+	}
+	if len(client.limiter.slots) != 0 {
+		t.Fatalf("request slots are not released, %d slots are held", len(client.limiter.slots))
+	}
+}
+
+func TestRequestLimiterSpacesQueuedRequests(t *testing.T) {
+	limiter := newRequestLimiter(1, 10)
+	release := limiter.acquire()
+
+	started := make(chan time.Time, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			release := limiter.acquire()
+			started <- time.Now()
+			release()
+		}()
+	}
+
+	// waiting for the slot does not use up the rate interval of the queued requests
+	time.Sleep(300 * time.Millisecond)
+	release()
+	first, second := <-started, <-started
+	if spacing := second.Sub(first); spacing < 90*time.Millisecond {
+		t.Fatalf("queued requests are sent %v apart, expected at least the rate interval", spacing)
+	}
+}
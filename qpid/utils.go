@@ -1,19 +1,27 @@
 package qpid
 
 import (
+	"bufio"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"golang.org/x/crypto/pkcs12"
+	"io"
 	"io/ioutil"
 	"log"
 	"math/big"
 	"net/http"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -93,7 +101,18 @@ func convertIfValueIsStringWhenPrimitiveIsExpected(value interface{}, schemaType
 	if value != nil {
 		stringValue, isString = value.(string)
 	}
-	if isString {
+	if number, isNumber := value.(json.Number); isNumber {
+		switch schemaType {
+		case schema.TypeInt:
+			var n int64
+			n, err = number.Int64()
+			value = int(n)
+		case schema.TypeFloat:
+			value, err = number.Float64()
+		default:
+			value = number.String()
+		}
+	} else if isString {
 		switch schemaType {
 		case schema.TypeInt:
 			log.Printf("Converting string '%s' to int", stringValue)
@@ -112,68 +131,61 @@ func convertIfValueIsStringWhenPrimitiveIsExpected(value interface{}, schemaType
 func convertHttpResponseToMap(res *http.Response) (*map[string]interface{}, error) {
 	var err error
 	defer func() {
-		closeError := res.Body.Close()
+		closeError := closeResponseBody(res)
 		if err == nil {
 			err = closeError
 		}
 	}()
 
-	if res.StatusCode >= http.StatusNotFound {
-		return &map[string]interface{}{}, nil
-	}
-
 	if res.StatusCode >= http.StatusBadRequest {
-		return getErrorResponse(res)
+		err = newQpidAPIError(res)
+		return &map[string]interface{}{}, err
 	}
 
-	body, err := ioutil.ReadAll(res.Body)
+	reader := bufio.NewReader(res.Body)
+	delimiter, err := peekJSONDelimiter(reader)
 	if err != nil {
 		return &map[string]interface{}{}, err
 	}
 
-	// try decoding as map
-	var m map[string]interface{}
-	err = json.Unmarshal([]byte(body), &m)
-	if err != nil {
-		// try decoding as array of maps
+	// the object can be returned as an array holding a single object
+	if delimiter == '[' {
 		var arr []map[string]interface{}
-		err2 := json.Unmarshal([]byte(body), &arr)
-		if err2 == nil && len(arr) == 1 {
-			m = arr[0]
-		} else {
+		err = newJSONDecoder(reader).Decode(&arr)
+		if err != nil {
+			return &map[string]interface{}{}, err
+		}
+		if len(arr) != 1 {
+			err = fmt.Errorf("expected single object, got %d objects", len(arr))
 			return &map[string]interface{}{}, err
 		}
+		return &arr[0], nil
 	}
 
+	var m map[string]interface{}
+	err = newJSONDecoder(reader).Decode(&m)
+	if err != nil {
+		return &map[string]interface{}{}, err
+	}
 	return &m, nil
 }
 
 func convertHttpResponseToArray(res *http.Response) (*[]map[string]interface{}, error) {
 	var err error
 	defer func() {
-		closeError := res.Body.Close()
+		closeError := closeResponseBody(res)
 		if err == nil {
 			err = closeError
 		}
 	}()
 
-	if res.StatusCode >= http.StatusNotFound {
-		return &[]map[string]interface{}{}, nil
-	}
-
 	if res.StatusCode >= http.StatusBadRequest {
-		_, err = getErrorResponse(res)
-		return &[]map[string]interface{}{}, err
-	}
-
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
+		err = newQpidAPIError(res)
 		return &[]map[string]interface{}{}, err
 	}
 
-	// try decoding as array of maps
 	var result []map[string]interface{}
-	err = json.Unmarshal([]byte(body), &result)
+	err = newJSONDecoder(res.Body).Decode(&result)
 	if err != nil {
 		return &[]map[string]interface{}{}, err
 	}
@@ -181,13 +193,37 @@ func convertHttpResponseToArray(res *http.Response) (*[]map[string]interface{},
 	return &result, nil
 }
 
-func getErrorResponse(res *http.Response) (*map[string]interface{}, error) {
-	var rme map[string]interface{}
-	err := json.NewDecoder(res.Body).Decode(&rme)
-	if rme["message"] != nil {
-		err = fmt.Errorf("error : %s", rme["message"])
+// newJSONDecoder creates decoder streaming JSON from given reader.
+// The numbers are decoded as json.Number in order to keep precision of large values.
+func newJSONDecoder(reader io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	return decoder
+}
+
+// peekJSONDelimiter returns the first non-whitespace character of JSON without consuming it
+func peekJSONDelimiter(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = reader.ReadByte()
+		default:
+			return b[0], nil
+		}
 	}
-	return &rme, err
+}
+
+// closeResponseBody discards the rest of the response body and closes it, thus, the connection can be reused
+func closeResponseBody(res *http.Response) error {
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	return res.Body.Close()
 }
 
 func schemaToAttributes(d *schema.ResourceData, schemaMap map[string]*schema.Schema, exclude ...string) *map[string]interface{} {
@@ -210,6 +246,14 @@ func schemaToAttributes(d *schema.ResourceData, schemaMap map[string]*schema.Sch
 	return &attributes
 }
 
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := time.ParseDuration(value); err != nil {
+		errors = append(errors, fmt.Errorf("%s: invalid duration '%s': %v", k, value, err))
+	}
+	return
+}
+
 func arrayOfStringsToMap(slice []string) map[string]struct{} {
 	set := make(map[string]struct{}, len(slice))
 	for _, s := range slice {
@@ -222,6 +266,12 @@ func arrayOfStringsToMap(slice []string) map[string]struct{} {
 func containsExpectedAttributes(actual *map[string]interface{}, expected *map[string]interface{}) bool {
 	for k, v := range *expected {
 		if val, ok := (*actual)[k]; ok {
+			// numbers are decoded as json.Number
+			if number, isNumber := val.(json.Number); isNumber {
+				if _, isFloat := v.(float64); isFloat {
+					val, _ = number.Float64()
+				}
+			}
 			if !reflect.DeepEqual(v, val) {
 				return false
 			}
@@ -252,6 +302,248 @@ func assertExpectedAndRemovedAttributes(actual *map[string]interface{}, expected
 	return nil
 }
 
+// dataSourceSchema derives the schema of a data source from the schema of the resource.
+// The given keys identify the object and are required, the other attributes are computed.
+// Sensitive attributes are not exposed by the data sources.
+func dataSourceSchema(resourceSchema map[string]*schema.Schema, required ...string) map[string]*schema.Schema {
+	requiredKeys := arrayOfStringsToMap(required)
+	result := make(map[string]*schema.Schema, len(resourceSchema))
+	for key, s := range resourceSchema {
+		if s.Sensitive {
+			continue
+		}
+		if _, isRequired := requiredKeys[key]; isRequired {
+			result[key] = &schema.Schema{Type: s.Type, Description: s.Description, Required: true}
+		} else {
+			result[key] = computedSchema(s)
+		}
+	}
+	return result
+}
+
+func computedSchema(s *schema.Schema) *schema.Schema {
+	computed := &schema.Schema{Type: s.Type, Description: s.Description, Computed: true}
+	switch elem := s.Elem.(type) {
+	case *schema.Resource:
+		nested := make(map[string]*schema.Schema, len(elem.Schema))
+		for key, item := range elem.Schema {
+			nested[key] = computedSchema(item)
+		}
+		computed.Elem = &schema.Resource{Schema: nested}
+	case *schema.Schema:
+		computed.Elem = &schema.Schema{Type: elem.Type}
+	}
+	return computed
+}
+
+// applyDataSourceAttributes sets the data source attributes from the broker object attributes
+func applyDataSourceAttributes(d *schema.ResourceData, schemaMap map[string]*schema.Schema, attributes *map[string]interface{}, exclude ...string) error {
+	id, err := objectID(attributes)
+	if err != nil {
+		return err
+	}
+	d.SetId(id)
+
+	values, err := toDataSourceAttributes(schemaMap, attributes, exclude...)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		err = d.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toDataSourceAttributes converts the broker object attributes into the values of the data source schema keys,
+// the required keys identifying the object are skipped
+func toDataSourceAttributes(schemaMap map[string]*schema.Schema, attributes *map[string]interface{}, exclude ...string) (map[string]interface{}, error) {
+	excludes := arrayOfStringsToMap(exclude)
+	values := map[string]interface{}{}
+	for key, s := range schemaMap {
+		if _, excluded := excludes[key]; excluded || s.Required {
+			continue
+		}
+
+		value, attributeSet := (*attributes)[convertToCamelCase(key)]
+		if !attributeSet || value == nil {
+			continue
+		}
+
+		value, err := toDataSourceValue(s, value)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected value of %s: %v", key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// virtualHostObjectsDataSourceSchema returns schema of the data source listing the objects of a virtual host
+// filtered by name regex, type and attribute values. The names of matching objects are exposed as names,
+// the attributes of matching objects are exposed by given key.
+func virtualHostObjectsDataSourceSchema(resourceSchema map[string]*schema.Schema, objectsKey string) map[string]*schema.Schema {
+	objectSchema := dataSourceSchema(resourceSchema)
+	delete(objectSchema, "virtual_host_node")
+	delete(objectSchema, "virtual_host")
+	objectSchema["id"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+
+	return map[string]*schema.Schema{
+		"virtual_host_node": {
+			Type:        schema.TypeString,
+			Description: "The name of Virtual Host Node",
+			Required:    true,
+		},
+		"virtual_host": {
+			Type:        schema.TypeString,
+			Description: "The name of Virtual Host",
+			Required:    true,
+		},
+		"name_regex": {
+			Type:         schema.TypeString,
+			Description:  "Regular expression the names of the objects need to match",
+			Optional:     true,
+			ValidateFunc: validation.ValidateRegexp,
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Description: "Type of the objects",
+			Optional:    true,
+		},
+		"attributes": {
+			Type:        schema.TypeMap,
+			Description: "Attribute values the objects need to have, for example, durable = true",
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"names": {
+			Type:        schema.TypeList,
+			Description: "Sorted names of the matching objects",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		objectsKey: {
+			Type:        schema.TypeList,
+			Description: "Attributes of the matching objects sorted by name",
+			Computed:    true,
+			Elem:        &schema.Resource{Schema: objectSchema},
+		},
+	}
+}
+
+// applyVirtualHostObjectsDataSourceAttributes sets the names and the attributes of the objects matching the data source filters
+func applyVirtualHostObjectsDataSourceAttributes(d *schema.ResourceData, schemaMap map[string]*schema.Schema, objectsKey string, objects *[]map[string]interface{}) error {
+	node := d.Get("virtual_host_node").(string)
+	host := d.Get("virtual_host").(string)
+
+	var nameRegex *regexp.Regexp
+	if value, ok := d.GetOk("name_regex"); ok {
+		var err error
+		nameRegex, err = regexp.Compile(value.(string))
+		if err != nil {
+			return fmt.Errorf("invalid name_regex '%s': %v", value, err)
+		}
+	}
+	objectType := d.Get("type").(string)
+	filter := d.Get("attributes").(map[string]interface{})
+
+	objectSchema := schemaMap[objectsKey].Elem.(*schema.Resource).Schema
+
+	matching := make([]map[string]interface{}, 0, len(*objects))
+	for _, attributes := range *objects {
+		if matchesFilter(attributes, nameRegex, objectType, filter) {
+			matching = append(matching, attributes)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return fmt.Sprintf("%v", matching[i]["name"]) < fmt.Sprintf("%v", matching[j]["name"])
+	})
+
+	names := make([]string, len(matching))
+	items := make([]interface{}, len(matching))
+	for i, attributes := range matching {
+		names[i] = fmt.Sprintf("%v", attributes["name"])
+		item, err := toDataSourceAttributes(objectSchema, &attributes)
+		if err != nil {
+			return fmt.Errorf("unexpected attributes of '%s': %v", names[i], err)
+		}
+		items[i] = item
+	}
+
+	d.SetId(node + "|" + host)
+	err := d.Set("names", names)
+	if err != nil {
+		return err
+	}
+	return d.Set(objectsKey, items)
+}
+
+// matchesFilter returns true when the object name matches the regex, and the object has given type and attribute values
+func matchesFilter(attributes map[string]interface{}, nameRegex *regexp.Regexp, objectType string, filter map[string]interface{}) bool {
+	if nameRegex != nil && !nameRegex.MatchString(fmt.Sprintf("%v", attributes["name"])) {
+		return false
+	}
+	if objectType != "" && attributes["type"] != objectType {
+		return false
+	}
+	for key, expected := range filter {
+		value, set := attributes[convertToCamelCase(key)]
+		if !set || value == nil || fmt.Sprintf("%v", value) != fmt.Sprintf("%v", expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// toDataSourceValue converts the value of broker attribute into the value of schema type
+func toDataSourceValue(s *schema.Schema, value interface{}) (interface{}, error) {
+	switch s.Type {
+	case schema.TypeList, schema.TypeSet:
+		if _, nested := s.Elem.(*schema.Resource); nested {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				return []interface{}{*createMapWithKeysUnderscored(&v)}, nil
+			case []interface{}:
+				items := make([]interface{}, len(v))
+				for i, item := range v {
+					m, isMap := item.(map[string]interface{})
+					if !isMap {
+						return nil, fmt.Errorf("expected object, got %v", item)
+					}
+					items[i] = *createMapWithKeysUnderscored(&m)
+				}
+				return items, nil
+			}
+		}
+		return value, nil
+	case schema.TypeMap:
+		if m, isMap := value.(map[string]interface{}); isMap {
+			return *convertToMapOfStrings(&m), nil
+		}
+		return value, nil
+	case schema.TypeString:
+		switch v := value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(data), nil
+		case json.Number:
+			return v.String(), nil
+		}
+		return value, nil
+	default:
+		return convertIfValueIsStringWhenPrimitiveIsExpected(value, s.Type)
+	}
+}
+
 func applyResourceAttributes(d *schema.ResourceData, attributes *map[string]interface{}, exclude ...string) error {
 	if len(*attributes) == 0 {
 		return nil
@@ -325,6 +617,29 @@ func generateSelfSigned(subject, host string) (*rsa.PrivateKey, *[]byte, error)
 	}
 }
 
+func loadPKCS12Certificate(file string, password string) (*tls.Certificate, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var pemData []byte
+	for _, block := range blocks {
+		pemData = append(pemData, pem.EncodeToMemory(block)...)
+	}
+
+	certificate, err := tls.X509KeyPair(pemData, pemData)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
 func certificateBytesToBase64(certificateBytes *[]byte) string {
 	return base64.StdEncoding.EncodeToString(*certificateBytes)
 }
@@ -337,3 +652,12 @@ func privateKeyToBase64(privateKey *rsa.PrivateKey) (string, error) {
 	}
 	return base64.StdEncoding.EncodeToString(privateKeyBytes), nil
 }
+
+// objectID returns id of the broker object with given attributes
+func objectID(attributes *map[string]interface{}) (string, error) {
+	id, ok := (*attributes)["id"].(string)
+	if !ok || id == "" {
+		return "", fmt.Errorf("id of qpid object '%v' is not returned by the broker", (*attributes)["name"])
+	}
+	return id, nil
+}